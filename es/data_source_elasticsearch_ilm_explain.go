@@ -0,0 +1,195 @@
+// Explain the index lifecycle state of indices in Elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/ilm-explain-lifecycle.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ILMExplainLifecycleResponse is the explain lifecycle API response
+type ILMExplainLifecycleResponse struct {
+	Indices map[string]ILMExplainLifecycleIndex `json:"indices"`
+}
+
+type ILMExplainLifecycleIndex struct {
+	Index                string          `json:"index"`
+	Managed              bool            `json:"managed"`
+	Policy               string          `json:"policy,omitempty"`
+	Age                  string          `json:"age,omitempty"`
+	Phase                string          `json:"phase,omitempty"`
+	Action               string          `json:"action,omitempty"`
+	Step                 string          `json:"step,omitempty"`
+	FailedStep           string          `json:"failed_step,omitempty"`
+	FailedStepRetryCount int64           `json:"failed_step_retry_count,omitempty"`
+	IsAutoRetryableError bool            `json:"is_auto_retryable_error,omitempty"`
+	StepInfo             json.RawMessage `json:"step_info,omitempty"`
+}
+
+// dataSourceElasticsearchILMExplain handle the explain lifecycle API call
+func dataSourceElasticsearchILMExplain() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceElasticsearchILMExplainRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"only_errors": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"only_managed": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"indices": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"index": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"managed": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"policy": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"age": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"phase": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"action": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"step": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"failed_step": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"failed_step_retry_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"is_auto_retryable_error": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"step_info": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataSourceElasticsearchILMExplainRead explain the lifecycle of indices
+func dataSourceElasticsearchILMExplainRead(d *schema.ResourceData, meta interface{}) (err error) {
+	index := d.Get("index").(string)
+
+	explain, err := explainIndexLifecycle(index, d.Get("only_errors").(bool), d.Get("only_managed").(bool), meta)
+	if err != nil {
+		return err
+	}
+
+	indices := make([]interface{}, 0, len(explain))
+	for _, item := range explain {
+		stepInfo := ""
+		if len(item.StepInfo) > 0 {
+			stepInfo = string(item.StepInfo)
+		}
+		indices = append(indices, map[string]interface{}{
+			"index":                   item.Index,
+			"managed":                 item.Managed,
+			"policy":                  item.Policy,
+			"age":                     item.Age,
+			"phase":                   item.Phase,
+			"action":                  item.Action,
+			"step":                    item.Step,
+			"failed_step":             item.FailedStep,
+			"failed_step_retry_count": item.FailedStepRetryCount,
+			"is_auto_retryable_error": item.IsAutoRetryableError,
+			"step_info":               stepInfo,
+		})
+	}
+
+	d.SetId(index)
+	if err = d.Set("indices", indices); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// explainIndexLifecycle return the lifecycle state of indices that match the pattern, sorted by index name
+func explainIndexLifecycle(index string, onlyErrors bool, onlyManaged bool, meta interface{}) (indices []ILMExplainLifecycleIndex, err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.ILM.ExplainLifecycle(
+		index,
+		client.API.ILM.ExplainLifecycle.WithOnlyErrors(onlyErrors),
+		client.API.ILM.ExplainLifecycle.WithOnlyManaged(onlyManaged),
+		client.API.ILM.ExplainLifecycle.WithContext(context.Background()),
+		client.API.ILM.ExplainLifecycle.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return []ILMExplainLifecycleIndex{}, nil
+		}
+		return nil, errors.Errorf("Error when explain lifecycle of %s: %s", index, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	explain := &ILMExplainLifecycleResponse{}
+	if err = json.Unmarshal(b, explain); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Explain lifecycle of %s: %s", index, string(b))
+
+	indices = make([]ILMExplainLifecycleIndex, 0, len(explain.Indices))
+	for _, item := range explain.Indices {
+		indices = append(indices, item)
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i].Index < indices[j].Index
+	})
+
+	return indices, nil
+}
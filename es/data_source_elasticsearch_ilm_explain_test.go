@@ -0,0 +1,53 @@
+package es
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccElasticsearchILMExplainDataSource(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchILMExplainPolicy,
+			},
+			{
+				PreConfig: func() {
+					testCreateElasticsearchIndex(t, "terraform-test-ilm-explain", `{"settings": {"index.lifecycle.name": "terraform-test-ilm-explain"}}`)
+				},
+				Config: testElasticsearchILMExplainDataSource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_ilm_explain.test", "indices.#", "1"),
+					resource.TestCheckResourceAttr("data.elasticsearch_ilm_explain.test", "indices.0.index", "terraform-test-ilm-explain"),
+					resource.TestCheckResourceAttr("data.elasticsearch_ilm_explain.test", "indices.0.managed", "true"),
+					resource.TestCheckResourceAttr("data.elasticsearch_ilm_explain.test", "indices.0.policy", "terraform-test-ilm-explain"),
+				),
+			},
+		},
+	})
+}
+
+var testElasticsearchILMExplainPolicy = `
+resource "elasticsearch_index_lifecycle_policy" "test" {
+  name = "terraform-test-ilm-explain"
+  warm {
+    min_age  = "10d"
+    readonly = true
+  }
+}
+`
+
+var testElasticsearchILMExplainDataSource = testElasticsearchILMExplainPolicy + `
+data "elasticsearch_ilm_explain" "test" {
+  index        = "terraform-test-ilm-explain*"
+  only_managed = true
+
+  depends_on = [elasticsearch_index_lifecycle_policy.test]
+}
+`
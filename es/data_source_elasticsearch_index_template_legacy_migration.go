@@ -0,0 +1,239 @@
+// Convert legacy index template to composable index template and component templates
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-templates.html
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/index-templates.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// IndexTemplateLegacy is the legacy index template
+type IndexTemplateLegacy struct {
+	Order         int64          `json:"order"`
+	Version       *int64         `json:"version,omitempty"`
+	IndexPatterns []string       `json:"index_patterns"`
+	Settings      map[string]any `json:"settings,omitempty"`
+	Mappings      map[string]any `json:"mappings,omitempty"`
+	Aliases       map[string]any `json:"aliases,omitempty"`
+}
+
+// IndexTemplateLegacyGetResponse is the get legacy index template API response
+type IndexTemplateLegacyGetResponse map[string]IndexTemplateLegacy
+
+// dataSourceElasticsearchIndexTemplateLegacyMigration handle the conversion of legacy index template
+func dataSourceElasticsearchIndexTemplateLegacyMigration() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceElasticsearchIndexTemplateLegacyMigrationRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"component_template_prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"priority": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"index_template": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"component_templates": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"template": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"overlapping_legacy_templates": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"differences": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// dataSourceElasticsearchIndexTemplateLegacyMigrationRead convert the legacy index template
+// Settings, mappings and aliases are split on component templates
+func dataSourceElasticsearchIndexTemplateLegacyMigrationRead(d *schema.ResourceData, meta interface{}) (err error) {
+	name := d.Get("name").(string)
+	prefix := d.Get("component_template_prefix").(string)
+	if prefix == "" {
+		prefix = name
+	}
+
+	templates, err := listIndexTemplatesLegacy(meta)
+	if err != nil {
+		return err
+	}
+	legacy, ok := templates[name]
+	if !ok {
+		return errors.Errorf("Legacy index template %s not found", name)
+	}
+
+	differences := []string{
+		"Legacy templates that match the same index are merged by order. Only the composable template with the highest priority is applied, the other matching composable templates are ignored.",
+		"Once created, the composable template take precedence over all legacy templates that match the same index.",
+	}
+
+	// Composable template priority must be positive
+	priority := legacy.Order
+	if priority < 0 {
+		priority = 0
+		differences = append(differences, fmt.Sprintf("The order %d is negative, it's mapped to priority 0.", legacy.Order))
+	} else {
+		differences = append(differences, fmt.Sprintf("The order %d is mapped to priority %d. Composable templates with the same priority and overlapping index patterns are rejected.", legacy.Order, priority))
+	}
+
+	names := make([]string, 0, len(templates))
+	for otherName := range templates {
+		names = append(names, otherName)
+	}
+	sort.Strings(names)
+	overlapping := make([]string, 0)
+	for _, otherName := range names {
+		if otherName == name {
+			continue
+		}
+		other := templates[otherName]
+		if pattern, otherPattern, isOverlap := getIndexPatternsOverlap(legacy.IndexPatterns, other.IndexPatterns); isOverlap {
+			overlapping = append(overlapping, otherName)
+			differences = append(differences, fmt.Sprintf("The legacy template %s (order %d) has the index pattern %s that overlap %s. Its settings, mappings and aliases are not merged anymore, add them on composed_of if needed.", otherName, other.Order, otherPattern, pattern))
+		}
+	}
+
+	// Split on component templates
+	componentTemplates := make([]interface{}, 0, 3)
+	composedOf := make([]string, 0, 3)
+	for _, section := range []struct {
+		kind  string
+		value map[string]any
+	}{
+		{kind: "settings", value: legacy.Settings},
+		{kind: "mappings", value: legacy.Mappings},
+		{kind: "aliases", value: legacy.Aliases},
+	} {
+		if len(section.value) == 0 {
+			continue
+		}
+		componentName := fmt.Sprintf("%s-%s", prefix, section.kind)
+		componentTemplate, err := convertInterfaceToJsonString(map[string]any{
+			"template": map[string]any{
+				section.kind: section.value,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		composedOf = append(composedOf, componentName)
+		componentTemplates = append(componentTemplates, map[string]interface{}{
+			"name":     componentName,
+			"type":     section.kind,
+			"template": componentTemplate,
+		})
+	}
+
+	indexTemplate, err := convertInterfaceToJsonString(&IndexTemplate{
+		IndexPatterns: legacy.IndexPatterns,
+		ComposedOf:    composedOf,
+		Priority:      &priority,
+		Version:       legacy.Version,
+		Meta: map[string]any{
+			"migrated_from": name,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Convert legacy index template %s: %s", name, indexTemplate)
+
+	d.SetId(name)
+	if err = d.Set("priority", int(priority)); err != nil {
+		return err
+	}
+	if err = d.Set("index_template", indexTemplate); err != nil {
+		return err
+	}
+	if err = d.Set("component_templates", componentTemplates); err != nil {
+		return err
+	}
+	if err = d.Set("overlapping_legacy_templates", overlapping); err != nil {
+		return err
+	}
+	if err = d.Set("differences", differences); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// listIndexTemplatesLegacy return all legacy index templates
+func listIndexTemplatesLegacy(meta interface{}) (templates IndexTemplateLegacyGetResponse, err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Indices.GetTemplate(
+		client.API.Indices.GetTemplate.WithContext(context.Background()),
+		client.API.Indices.GetTemplate.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	templates = IndexTemplateLegacyGetResponse{}
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return templates, nil
+		}
+		return nil, errors.Errorf("Error when get legacy index templates: %s", res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &templates); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
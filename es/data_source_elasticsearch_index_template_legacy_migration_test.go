@@ -0,0 +1,73 @@
+package es
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccElasticsearchIndexTemplateLegacyMigrationDataSource(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchIndexTemplateLegacyMigrationDataSource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_index_template_legacy_migration.test", "priority", "5"),
+					resource.TestCheckResourceAttr("data.elasticsearch_index_template_legacy_migration.test", "component_templates.#", "2"),
+					resource.TestCheckResourceAttr("data.elasticsearch_index_template_legacy_migration.test", "component_templates.0.name", "terraform-test-migration-settings"),
+					resource.TestCheckResourceAttr("data.elasticsearch_index_template_legacy_migration.test", "component_templates.1.name", "terraform-test-migration-mappings"),
+					resource.TestCheckResourceAttr("data.elasticsearch_index_template_legacy_migration.test", "overlapping_legacy_templates.#", "1"),
+					resource.TestCheckResourceAttr("data.elasticsearch_index_template_legacy_migration.test", "overlapping_legacy_templates.0", "terraform-test-migration-base"),
+					resource.TestMatchResourceAttr("data.elasticsearch_index_template_legacy_migration.test", "index_template", regexp.MustCompile(`"priority":5`)),
+				),
+			},
+		},
+	})
+}
+
+var testElasticsearchIndexTemplateLegacyMigrationDataSource = `
+resource "elasticsearch_index_template_legacy" "base" {
+  name     = "terraform-test-migration-base"
+  template = <<EOF
+{
+  "index_patterns": ["test-migration-*"],
+  "order": 0,
+  "settings": {
+    "index.number_of_replicas": "0"
+  }
+}
+EOF
+}
+
+resource "elasticsearch_index_template_legacy" "test" {
+  name     = "terraform-test-migration"
+  template = <<EOF
+{
+  "index_patterns": ["test-migration-logs-*"],
+  "order": 5,
+  "settings": {
+    "index.refresh_interval": "5s"
+  },
+  "mappings": {
+    "properties": {
+      "message": {
+        "type": "text"
+      }
+    }
+  }
+}
+EOF
+}
+
+data "elasticsearch_index_template_legacy_migration" "test" {
+  name = elasticsearch_index_template_legacy.test.id
+
+  depends_on = [elasticsearch_index_template_legacy.base]
+}
+`
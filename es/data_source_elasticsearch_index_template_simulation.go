@@ -0,0 +1,216 @@
+// Simulate the index template resolution in Elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-simulate-index.html
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-simulate-template.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// IndexTemplateSimulateResponse is the simulate index and simulate template API response
+type IndexTemplateSimulateResponse struct {
+	Template struct {
+		Settings json.RawMessage `json:"settings,omitempty"`
+		Mappings json.RawMessage `json:"mappings,omitempty"`
+		Aliases  json.RawMessage `json:"aliases,omitempty"`
+	} `json:"template"`
+	Overlapping []struct {
+		Name          string   `json:"name"`
+		IndexPatterns []string `json:"index_patterns"`
+	} `json:"overlapping,omitempty"`
+}
+
+// dataSourceElasticsearchIndexTemplateSimulation handle the simulate index and simulate template API call
+func dataSourceElasticsearchIndexTemplateSimulation() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceElasticsearchIndexTemplateSimulationRead,
+
+		Schema: map[string]*schema.Schema{
+			"index_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"index_name", "template_name", "template"},
+			},
+			"template_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"template": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsJSON,
+			},
+			"matched_template": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"settings": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"mappings": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"aliases": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"overlapping": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"index_patterns": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataSourceElasticsearchIndexTemplateSimulationRead resolve the template for an index name, an existing template or a pending template
+func dataSourceElasticsearchIndexTemplateSimulationRead(d *schema.ResourceData, meta interface{}) (err error) {
+	indexName := d.Get("index_name").(string)
+	templateName := d.Get("template_name").(string)
+	template := d.Get("template").(string)
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	var (
+		res *esapi.Response
+		id  string
+	)
+	switch {
+	case indexName != "":
+		id = indexName
+		res, err = client.API.Indices.SimulateIndexTemplate(
+			indexName,
+			client.API.Indices.SimulateIndexTemplate.WithContext(context.Background()),
+			client.API.Indices.SimulateIndexTemplate.WithPretty(),
+		)
+	case templateName != "":
+		id = templateName
+		res, err = client.API.Indices.SimulateTemplate(
+			client.API.Indices.SimulateTemplate.WithName(templateName),
+			client.API.Indices.SimulateTemplate.WithContext(context.Background()),
+			client.API.Indices.SimulateTemplate.WithPretty(),
+		)
+	default:
+		id = "template"
+		res, err = client.API.Indices.SimulateTemplate(
+			client.API.Indices.SimulateTemplate.WithBody(bytes.NewReader([]byte(template))),
+			client.API.Indices.SimulateTemplate.WithContext(context.Background()),
+			client.API.Indices.SimulateTemplate.WithPretty(),
+		)
+	}
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when simulate index template %s: %s", id, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	simulate := &IndexTemplateSimulateResponse{}
+	if err = json.Unmarshal(b, simulate); err != nil {
+		return err
+	}
+
+	log.Debugf("Simulate index template %s: %s", id, string(b))
+
+	matchedTemplate := templateName
+	if indexName != "" {
+		if matchedTemplate, err = getMatchedIndexTemplate(indexName, meta); err != nil {
+			return err
+		}
+	}
+
+	overlapping := make([]interface{}, 0, len(simulate.Overlapping))
+	for _, item := range simulate.Overlapping {
+		overlapping = append(overlapping, map[string]interface{}{
+			"name":           item.Name,
+			"index_patterns": item.IndexPatterns,
+		})
+	}
+
+	d.SetId(id)
+	if err = d.Set("matched_template", matchedTemplate); err != nil {
+		return err
+	}
+	if err = d.Set("settings", string(simulate.Template.Settings)); err != nil {
+		return err
+	}
+	if err = d.Set("mappings", string(simulate.Template.Mappings)); err != nil {
+		return err
+	}
+	if err = d.Set("aliases", string(simulate.Template.Aliases)); err != nil {
+		return err
+	}
+	if err = d.Set("overlapping", overlapping); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getMatchedIndexTemplate return the index template with the highest priority that match the index name
+// It return empty string if no template match
+func getMatchedIndexTemplate(indexName string, meta interface{}) (name string, err error) {
+	templates, err := listIndexTemplates(meta)
+	if err != nil {
+		return "", err
+	}
+
+	var priority int64 = -1
+	for _, template := range templates.IndexTemplates {
+		templatePriority := getIndexTemplatePriority(&template.IndexTemplate)
+		if templatePriority <= priority {
+			continue
+		}
+		for _, pattern := range template.IndexTemplate.IndexPatterns {
+			if matchIndexPattern(pattern, indexName) {
+				name = template.Name
+				priority = templatePriority
+				break
+			}
+		}
+	}
+
+	return name, nil
+}
+
+// matchIndexPattern check if the index name match the index pattern, only the wildcard `*` is supported like Elasticsearch
+func matchIndexPattern(pattern string, indexName string) bool {
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	return regexp.MustCompile(expr).MatchString(indexName)
+}
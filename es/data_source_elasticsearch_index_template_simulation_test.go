@@ -0,0 +1,102 @@
+package es
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccElasticsearchIndexTemplateSimulationDataSource(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchIndexTemplateSimulationDataSource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_index_template_simulation.index", "matched_template", "terraform-test-simulation-high"),
+					resource.TestCheckResourceAttr("data.elasticsearch_index_template_simulation.index", "overlapping.#", "1"),
+					resource.TestCheckResourceAttr("data.elasticsearch_index_template_simulation.index", "overlapping.0.name", "terraform-test-simulation-low"),
+					resource.TestMatchResourceAttr("data.elasticsearch_index_template_simulation.index", "settings", regexp.MustCompile(`"refresh_interval"\s*:\s*"5s"`)),
+					resource.TestMatchResourceAttr("data.elasticsearch_index_template_simulation.index", "mappings", regexp.MustCompile("host_name")),
+					resource.TestCheckResourceAttr("data.elasticsearch_index_template_simulation.template", "matched_template", "terraform-test-simulation-low"),
+					resource.TestMatchResourceAttr("data.elasticsearch_index_template_simulation.pending", "mappings", regexp.MustCompile("message")),
+				),
+			},
+		},
+	})
+}
+
+var testElasticsearchIndexTemplateSimulationDataSource = `
+resource "elasticsearch_index_component_template" "test" {
+  name 		= "terraform-test-simulation"
+  template 	= <<EOF
+{
+	"template": {
+		"settings": {
+			"index.refresh_interval": "5s"
+		},
+		"mappings": {
+			"properties": {
+				"host_name": {
+					"type": "keyword"
+				}
+			}
+		}
+	}
+}
+EOF
+}
+
+resource "elasticsearch_index_template" "low" {
+  name 		= "terraform-test-simulation-low"
+  template 	= <<EOF
+{
+	"index_patterns": ["test-simulation-*"],
+	"priority": 1
+}
+EOF
+}
+
+resource "elasticsearch_index_template" "high" {
+  name 		= "terraform-test-simulation-high"
+  template 	= <<EOF
+{
+	"index_patterns": ["test-simulation-logs-*"],
+	"composed_of": ["${elasticsearch_index_component_template.test.id}"],
+	"priority": 10
+}
+EOF
+}
+
+data "elasticsearch_index_template_simulation" "index" {
+  index_name = "test-simulation-logs-1"
+
+  depends_on = [elasticsearch_index_template.low, elasticsearch_index_template.high]
+}
+
+data "elasticsearch_index_template_simulation" "template" {
+  template_name = elasticsearch_index_template.low.name
+}
+
+data "elasticsearch_index_template_simulation" "pending" {
+  template = <<EOF
+{
+	"index_patterns": ["test-simulation-pending-*"],
+	"template": {
+		"mappings": {
+			"properties": {
+				"message": {
+					"type": "text"
+				}
+			}
+		}
+	}
+}
+EOF
+}
+`
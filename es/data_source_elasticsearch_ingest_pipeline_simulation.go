@@ -0,0 +1,275 @@
+// Simulate ingest pipeline on sample documents in Elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/simulate-pipeline-api.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// IngestSimulateRequest is the simulate pipeline API body
+type IngestSimulateRequest struct {
+	Pipeline json.RawMessage          `json:"pipeline"`
+	Docs     []IngestSimulateDocument `json:"docs"`
+}
+
+type IngestSimulateDocument struct {
+	Index  string         `json:"_index,omitempty"`
+	Id     string         `json:"_id,omitempty"`
+	Source map[string]any `json:"_source"`
+}
+
+// IngestSimulateResponse is the simulate pipeline API response with verbose
+type IngestSimulateResponse struct {
+	Docs []IngestSimulateResult `json:"docs"`
+}
+
+type IngestSimulateResult struct {
+	Doc              *IngestSimulateDocument         `json:"doc,omitempty"`
+	Error            json.RawMessage                 `json:"error,omitempty"`
+	ProcessorResults []IngestSimulateProcessorResult `json:"processor_results,omitempty"`
+}
+
+type IngestSimulateProcessorResult struct {
+	ProcessorType string                  `json:"processor_type,omitempty"`
+	Tag           string                  `json:"tag,omitempty"`
+	Status        string                  `json:"status,omitempty"`
+	Doc           *IngestSimulateDocument `json:"doc,omitempty"`
+	Error         json.RawMessage         `json:"error,omitempty"`
+}
+
+// dataSourceElasticsearchIngestPipelineSimulation handle the simulate pipeline API call
+func dataSourceElasticsearchIngestPipelineSimulation() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceElasticsearchIngestPipelineSimulationRead,
+
+		Schema: map[string]*schema.Schema{
+			"pipeline": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsJSON,
+			},
+			"test_documents": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsJSON,
+			},
+			"expected_documents": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsJSON,
+			},
+			"fail_on_error": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"documents": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"results": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"document": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"error": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"processor_results": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataSourceElasticsearchIngestPipelineSimulationRead run the pipeline on test documents and compare the result with the expected documents
+func dataSourceElasticsearchIngestPipelineSimulationRead(d *schema.ResourceData, meta interface{}) (err error) {
+	pipeline := d.Get("pipeline").(string)
+
+	docs, err := expandIngestSimulateDocuments(d.Get("test_documents").(string))
+	if err != nil {
+		return errors.Wrap(err, "Error when read test_documents")
+	}
+	var expectedDocs []IngestSimulateDocument
+	if raw := d.Get("expected_documents").(string); raw != "" {
+		if expectedDocs, err = expandIngestSimulateDocuments(raw); err != nil {
+			return errors.Wrap(err, "Error when read expected_documents")
+		}
+		if len(expectedDocs) != len(docs) {
+			return errors.Errorf("expected_documents must have the same number of documents than test_documents: %d != %d", len(expectedDocs), len(docs))
+		}
+	}
+
+	simulate, err := simulateIngestPipeline(pipeline, docs, meta)
+	if err != nil {
+		return err
+	}
+
+	failures := make([]string, 0)
+	documents := make([]any, 0, len(simulate.Docs))
+	results := make([]interface{}, 0, len(simulate.Docs))
+	for i, result := range simulate.Docs {
+		doc, errorRaw := getIngestSimulateResult(&result)
+		if len(errorRaw) > 0 {
+			failures = append(failures, fmt.Sprintf("  - document %d: %s", i, string(errorRaw)))
+		} else if expectedDocs != nil {
+			diff, err := eshandler.StandardDiff(doc, expectedDocs[i].Source, logEntry, nil)
+			if err != nil {
+				return err
+			}
+			if diff != "" {
+				failures = append(failures, fmt.Sprintf("  - document %d not match expected document: %s", i, diff))
+			}
+		}
+
+		documentJSON, err := convertInterfaceToJsonString(doc)
+		if err != nil {
+			return err
+		}
+		processorResultsJSON, err := convertInterfaceToJsonString(result.ProcessorResults)
+		if err != nil {
+			return err
+		}
+		documents = append(documents, doc)
+		results = append(results, map[string]interface{}{
+			"document":          documentJSON,
+			"error":             string(errorRaw),
+			"processor_results": processorResultsJSON,
+		})
+	}
+
+	if d.Get("fail_on_error").(bool) && len(failures) > 0 {
+		return errors.Errorf("Ingest pipeline simulation failed:\n%s", strings.Join(failures, "\n"))
+	}
+
+	documentsJSON, err := convertInterfaceToJsonString(documents)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(pipeline+d.Get("test_documents").(string)))))
+	if err = d.Set("documents", documentsJSON); err != nil {
+		return err
+	}
+	if err = d.Set("results", results); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// expandIngestSimulateDocuments read the documents from JSON array
+// The document can be the source or a full document with _index, _id and _source
+func expandIngestSimulateDocuments(raw string) (docs []IngestSimulateDocument, err error) {
+	items := make([]map[string]any, 0)
+	if err = json.Unmarshal([]byte(raw), &items); err != nil {
+		return nil, err
+	}
+
+	docs = make([]IngestSimulateDocument, 0, len(items))
+	for _, item := range items {
+		source, isDocument := item["_source"].(map[string]any)
+		if !isDocument {
+			docs = append(docs, IngestSimulateDocument{Source: item})
+			continue
+		}
+		docs = append(docs, IngestSimulateDocument{
+			Index:  getString(item, "_index"),
+			Id:     getString(item, "_id"),
+			Source: source,
+		})
+	}
+
+	return docs, nil
+}
+
+// getIngestSimulateResult return the final document or the error of the simulation
+// With verbose mode, the document fail when the last processor run is on error, else the error was handled by on_failure or ignore_failure
+// The document is nil when it's dropped
+func getIngestSimulateResult(result *IngestSimulateResult) (doc map[string]any, errorRaw json.RawMessage) {
+	if len(result.Error) > 0 {
+		return nil, result.Error
+	}
+	if result.Doc != nil {
+		return result.Doc.Source, nil
+	}
+
+	for _, processorResult := range result.ProcessorResults {
+		switch {
+		case processorResult.Status == "dropped":
+			doc = nil
+		case processorResult.Doc != nil:
+			doc = processorResult.Doc.Source
+		}
+	}
+	if nb := len(result.ProcessorResults); nb > 0 && result.ProcessorResults[nb-1].Status == "error" {
+		return doc, result.ProcessorResults[nb-1].Error
+	}
+
+	return doc, nil
+}
+
+// simulateIngestPipeline run the pipeline definition on documents with verbose mode
+func simulateIngestPipeline(pipeline string, docs []IngestSimulateDocument, meta interface{}) (simulate *IngestSimulateResponse, err error) {
+	data := &IngestSimulateRequest{
+		Pipeline: json.RawMessage(pipeline),
+		Docs:     docs,
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Ingest.Simulate(
+		bytes.NewReader(b),
+		client.API.Ingest.Simulate.WithVerbose(true),
+		client.API.Ingest.Simulate.WithContext(context.Background()),
+		client.API.Ingest.Simulate.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, errors.Errorf("Error when simulate ingest pipeline: %s", res.String())
+	}
+
+	b, err = ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	simulate = &IngestSimulateResponse{}
+	if err = json.Unmarshal(b, simulate); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Simulate ingest pipeline: %s", string(b))
+
+	return simulate, nil
+}
@@ -0,0 +1,113 @@
+package es
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccElasticsearchIngestPipelineSimulationDataSource(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchIngestPipelineSimulationDataSource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_ingest_pipeline_simulation.test", "results.#", "1"),
+					resource.TestCheckResourceAttr("data.elasticsearch_ingest_pipeline_simulation.test", "results.0.error", ""),
+					resource.TestCheckResourceAttrSet("data.elasticsearch_ingest_pipeline_simulation.test", "results.0.processor_results"),
+					resource.TestCheckResourceAttrSet("data.elasticsearch_ingest_pipeline_simulation.test", "documents"),
+				),
+			},
+			{
+				Config:      testElasticsearchIngestPipelineSimulationDataSourceMismatch,
+				ExpectError: regexp.MustCompile("not match expected document"),
+			},
+			{
+				Config:      testElasticsearchIngestPipelineSimulationDataSourceError,
+				ExpectError: regexp.MustCompile("Ingest pipeline simulation failed"),
+			},
+		},
+	})
+}
+
+var testElasticsearchIngestPipelineSimulationDataSource = `
+data "elasticsearch_ingest_pipeline_simulation" "test" {
+  pipeline = <<EOF
+{
+  "processors" : [
+    {
+      "grok" : {
+        "field" : "message",
+        "patterns" : ["%{IP:client.ip} %{WORD:http.method}"]
+      }
+    }
+  ]
+}
+EOF
+  test_documents = <<EOF
+[
+  { "message" : "10.0.0.1 GET" }
+]
+EOF
+  expected_documents = <<EOF
+[
+  { "message" : "10.0.0.1 GET", "client" : { "ip" : "10.0.0.1" }, "http" : { "method" : "GET" } }
+]
+EOF
+}
+`
+
+var testElasticsearchIngestPipelineSimulationDataSourceMismatch = `
+data "elasticsearch_ingest_pipeline_simulation" "test" {
+  pipeline = <<EOF
+{
+  "processors" : [
+    {
+      "grok" : {
+        "field" : "message",
+        "patterns" : ["%{IP:client.ip} %{WORD:http.method}"]
+      }
+    }
+  ]
+}
+EOF
+  test_documents = <<EOF
+[
+  { "message" : "10.0.0.1 GET" }
+]
+EOF
+  expected_documents = <<EOF
+[
+  { "message" : "10.0.0.1 GET", "client" : { "ip" : "10.0.0.2" }, "http" : { "method" : "GET" } }
+]
+EOF
+}
+`
+
+var testElasticsearchIngestPipelineSimulationDataSourceError = `
+data "elasticsearch_ingest_pipeline_simulation" "test" {
+  pipeline = <<EOF
+{
+  "processors" : [
+    {
+      "grok" : {
+        "field" : "message",
+        "patterns" : ["%{IP:client.ip} %{WORD:http.method}"]
+      }
+    }
+  ]
+}
+EOF
+  test_documents = <<EOF
+[
+  { "message" : "not an ip" }
+]
+EOF
+}
+`
@@ -0,0 +1,261 @@
+// Execute a watch in simulate mode in Elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/watcher-api-execute-watch.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// WatcherExecuteWatchRequest is the execute watch API body
+type WatcherExecuteWatchRequest struct {
+	Watch            map[string]interface{} `json:"watch"`
+	ActionModes      map[string]string      `json:"action_modes,omitempty"`
+	AlternativeInput interface{}            `json:"alternative_input,omitempty"`
+	IgnoreCondition  bool                   `json:"ignore_condition"`
+	RecordExecution  bool                   `json:"record_execution"`
+}
+
+// WatcherExecuteWatchResponse is the execute watch API response
+type WatcherExecuteWatchResponse struct {
+	Id          string                    `json:"_id"`
+	WatchRecord WatcherExecuteWatchRecord `json:"watch_record"`
+}
+
+type WatcherExecuteWatchRecord struct {
+	State    string                    `json:"state"`
+	Result   WatcherExecuteWatchResult `json:"result"`
+	Messages []string                  `json:"messages,omitempty"`
+}
+
+type WatcherExecuteWatchResult struct {
+	Input     *WatcherExecuteWatchStepResult  `json:"input,omitempty"`
+	Condition *WatcherExecuteWatchStepResult  `json:"condition,omitempty"`
+	Transform *WatcherExecuteWatchStepResult  `json:"transform,omitempty"`
+	Actions   []WatcherExecuteWatchStepResult `json:"actions,omitempty"`
+}
+
+type WatcherExecuteWatchStepResult struct {
+	Id     string          `json:"id,omitempty"`
+	Type   string          `json:"type,omitempty"`
+	Status string          `json:"status,omitempty"`
+	Met    bool            `json:"met,omitempty"`
+	Reason string          `json:"reason,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+// dataSourceElasticsearchWatchExecution handle the execute watch API call
+func dataSourceElasticsearchWatchExecution() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceElasticsearchWatchExecutionRead,
+
+		Schema: map[string]*schema.Schema{
+			"trigger": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"input": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"condition": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"transform": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"actions": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"metadata": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"throttle_period": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"alternative_input": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"action_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "simulate",
+				ValidateFunc: validation.StringInSlice([]string{"simulate", "force_simulate", "skip"}, false),
+			},
+			"ignore_condition": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"fail_on_error": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"condition_met": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"actions_status": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"result": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// dataSourceElasticsearchWatchExecutionRead execute the inline watch and check the result
+func dataSourceElasticsearchWatchExecutionRead(d *schema.ResourceData, meta interface{}) (err error) {
+	watch := map[string]interface{}{}
+	for _, key := range []string{"trigger", "input", "condition", "transform", "actions", "metadata"} {
+		if raw := d.Get(key).(string); raw != "" {
+			watch[key] = json.RawMessage(raw)
+		}
+	}
+	if throttlePeriod := d.Get("throttle_period").(string); throttlePeriod != "" {
+		watch["throttle_period"] = throttlePeriod
+	}
+
+	data := &WatcherExecuteWatchRequest{
+		Watch: watch,
+		ActionModes: map[string]string{
+			"_all": d.Get("action_mode").(string),
+		},
+		AlternativeInput: optionalInterfaceJSON(d.Get("alternative_input").(string)),
+		IgnoreCondition:  d.Get("ignore_condition").(bool),
+		RecordExecution:  false,
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Watcher.ExecuteWatch(
+		client.API.Watcher.ExecuteWatch.WithBody(bytes.NewReader(b)),
+		client.API.Watcher.ExecuteWatch.WithContext(context.Background()),
+		client.API.Watcher.ExecuteWatch.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when execute watch: %s", res.String())
+	}
+
+	b, err = ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	execute := &WatcherExecuteWatchResponse{}
+	if err = json.Unmarshal(b, execute); err != nil {
+		return err
+	}
+	record := execute.WatchRecord
+
+	log.Debugf("Execute watch: %s", string(b))
+
+	if d.Get("fail_on_error").(bool) {
+		if failures := getWatchExecutionFailures(&record); len(failures) > 0 {
+			return errors.Errorf("Watch execution failed with state %s:\n%s", record.State, strings.Join(failures, "\n"))
+		}
+	}
+
+	actionsStatus := make(map[string]interface{}, len(record.Result.Actions))
+	for _, action := range record.Result.Actions {
+		actionsStatus[action.Id] = action.Status
+	}
+	conditionMet := record.Result.Condition != nil && record.Result.Condition.Met
+
+	// Keep the raw result to not lose the payload
+	rawResponse := map[string]json.RawMessage{}
+	if err = json.Unmarshal(b, &rawResponse); err != nil {
+		return err
+	}
+	rawRecord := map[string]json.RawMessage{}
+	if err = json.Unmarshal(rawResponse["watch_record"], &rawRecord); err != nil {
+		return err
+	}
+
+	d.SetId(execute.Id)
+	if err = d.Set("state", record.State); err != nil {
+		return err
+	}
+	if err = d.Set("condition_met", conditionMet); err != nil {
+		return err
+	}
+	if err = d.Set("actions_status", actionsStatus); err != nil {
+		return err
+	}
+	if err = d.Set("result", string(rawRecord["result"])); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getWatchExecutionFailures return the list of failures from input, condition, transform and actions
+func getWatchExecutionFailures(record *WatcherExecuteWatchRecord) (failures []string) {
+	failures = make([]string, 0)
+	steps := map[string]*WatcherExecuteWatchStepResult{
+		"input":     record.Result.Input,
+		"condition": record.Result.Condition,
+		"transform": record.Result.Transform,
+	}
+	for _, name := range []string{"input", "condition", "transform"} {
+		if step := steps[name]; step != nil && step.Status == "failure" {
+			failures = append(failures, formatWatchExecutionFailure(name, step))
+		}
+	}
+	for i := range record.Result.Actions {
+		if action := &record.Result.Actions[i]; action.Status == "failure" {
+			failures = append(failures, formatWatchExecutionFailure("action "+action.Id, action))
+		}
+	}
+
+	if len(failures) == 0 && record.State == "failed" {
+		failures = append(failures, record.Messages...)
+	}
+
+	return failures
+}
+
+// formatWatchExecutionFailure return the failure reason of a step
+func formatWatchExecutionFailure(name string, step *WatcherExecuteWatchStepResult) string {
+	reason := step.Reason
+	if reason == "" && len(step.Error) > 0 {
+		reason = string(step.Error)
+	}
+	return "  - " + name + ": " + reason
+}
@@ -0,0 +1,87 @@
+package es
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccElasticsearchWatchExecutionDataSource(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchWatchExecutionDataSource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_watch_execution.test", "state", "executed"),
+					resource.TestCheckResourceAttr("data.elasticsearch_watch_execution.test", "condition_met", "true"),
+					resource.TestCheckResourceAttr("data.elasticsearch_watch_execution.test", "actions_status.log", "simulated"),
+					resource.TestCheckResourceAttrSet("data.elasticsearch_watch_execution.test", "result"),
+				),
+			},
+			{
+				Config:      testElasticsearchWatchExecutionDataSourceFailure,
+				ExpectError: regexp.MustCompile("Watch execution failed"),
+			},
+		},
+	})
+}
+
+var testElasticsearchWatchExecutionDataSource = `
+data "elasticsearch_watch_execution" "test" {
+  trigger = <<EOF
+{
+  "schedule" : { "interval" : "1h" }
+}
+EOF
+  input = <<EOF
+{
+  "simple" : { "hits" : { "total" : 1 } }
+}
+EOF
+  condition = <<EOF
+{
+  "compare" : { "ctx.payload.hits.total" : { "gt" : 0 } }
+}
+EOF
+  actions = <<EOF
+{
+  "log" : {
+    "logging" : {
+      "text" : "{{ctx.payload.hits.total}} hits"
+    }
+  }
+}
+EOF
+  alternative_input = <<EOF
+{
+  "hits" : { "total" : 2 }
+}
+EOF
+}
+`
+
+var testElasticsearchWatchExecutionDataSourceFailure = `
+data "elasticsearch_watch_execution" "test" {
+  trigger = <<EOF
+{
+  "schedule" : { "interval" : "1h" }
+}
+EOF
+  input = <<EOF
+{
+  "simple" : { "hits" : { "total" : 1 } }
+}
+EOF
+  condition = <<EOF
+{
+  "script" : { "source" : "return ctx.payload.unknown.field > 0" }
+}
+EOF
+}
+`
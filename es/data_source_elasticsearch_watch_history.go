@@ -0,0 +1,219 @@
+// Get the last executions of a watch from the watch history in Elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/how-watcher-works.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// WatchHistoryIndexPattern is the index pattern of the watch history
+const WatchHistoryIndexPattern = ".watcher-history-*"
+
+// WatchHistorySearchResponse is the search API response on watch history
+type WatchHistorySearchResponse struct {
+	Hits struct {
+		Hits []struct {
+			Id     string             `json:"_id"`
+			Source WatchHistoryRecord `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+type WatchHistoryRecord struct {
+	WatchId      string   `json:"watch_id"`
+	State        string   `json:"state"`
+	Messages     []string `json:"messages,omitempty"`
+	TriggerEvent struct {
+		TriggeredTime string `json:"triggered_time"`
+	} `json:"trigger_event"`
+	Result struct {
+		ExecutionTime     string `json:"execution_time"`
+		ExecutionDuration int64  `json:"execution_duration"`
+		Condition         *struct {
+			Met bool `json:"met"`
+		} `json:"condition,omitempty"`
+		Actions []struct {
+			Id     string `json:"id"`
+			Status string `json:"status"`
+		} `json:"actions,omitempty"`
+	} `json:"result"`
+}
+
+// dataSourceElasticsearchWatchHistory handle the search on watch history
+func dataSourceElasticsearchWatchHistory() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceElasticsearchWatchHistoryRead,
+
+		Schema: map[string]*schema.Schema{
+			"watch_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntBetween(1, 10000),
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"executions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"triggered_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"execution_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"execution_duration": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"condition_met": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"actions_status": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"messages": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataSourceElasticsearchWatchHistoryRead search the last executions of the watch, the most recent first
+func dataSourceElasticsearchWatchHistoryRead(d *schema.ResourceData, meta interface{}) (err error) {
+	watchID := d.Get("watch_id").(string)
+	state := d.Get("state").(string)
+
+	filters := []interface{}{
+		map[string]interface{}{
+			"term": map[string]interface{}{
+				"watch_id": watchID,
+			},
+		},
+	}
+	if state != "" {
+		filters = append(filters, map[string]interface{}{
+			"term": map[string]interface{}{
+				"state": state,
+			},
+		})
+	}
+	query := map[string]interface{}{
+		"size": d.Get("size").(int),
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": filters,
+			},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{
+				"trigger_event.triggered_time": map[string]interface{}{
+					"order":         "desc",
+					"unmapped_type": "date",
+				},
+			},
+		},
+	}
+	b, err := json.Marshal(query)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Search(
+		client.API.Search.WithIndex(WatchHistoryIndexPattern),
+		client.API.Search.WithBody(bytes.NewReader(b)),
+		client.API.Search.WithExpandWildcards("open,hidden"),
+		client.API.Search.WithIgnoreUnavailable(true),
+		client.API.Search.WithAllowNoIndices(true),
+		client.API.Search.WithContext(context.Background()),
+		client.API.Search.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when search watch history of %s: %s", watchID, res.String())
+	}
+
+	b, err = ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	history := &WatchHistorySearchResponse{}
+	if err = json.Unmarshal(b, history); err != nil {
+		return err
+	}
+
+	log.Debugf("Found %d executions for watch %s", len(history.Hits.Hits), watchID)
+
+	executions := make([]interface{}, 0, len(history.Hits.Hits))
+	for _, hit := range history.Hits.Hits {
+		record := hit.Source
+		actionsStatus := make(map[string]interface{}, len(record.Result.Actions))
+		for _, action := range record.Result.Actions {
+			actionsStatus[action.Id] = action.Status
+		}
+		executions = append(executions, map[string]interface{}{
+			"id":                 hit.Id,
+			"state":              record.State,
+			"triggered_time":     record.TriggerEvent.TriggeredTime,
+			"execution_time":     record.Result.ExecutionTime,
+			"execution_duration": int(record.Result.ExecutionDuration),
+			"condition_met":      record.Result.Condition != nil && record.Result.Condition.Met,
+			"actions_status":     actionsStatus,
+			"messages":           record.Messages,
+		})
+	}
+
+	d.SetId(watchID)
+	if err = d.Set("executions", executions); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package es
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccElasticsearchWatchHistoryDataSource(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchWatchHistoryWatch,
+			},
+			{
+				PreConfig: func() {
+					testExecuteElasticsearchWatch(t, "terraform-test-history")
+				},
+				Config: testElasticsearchWatchHistoryDataSource,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.elasticsearch_watch_history.test", "executions.#", "1"),
+					resource.TestCheckResourceAttr("data.elasticsearch_watch_history.test", "executions.0.state", "executed"),
+					resource.TestCheckResourceAttr("data.elasticsearch_watch_history.test", "executions.0.condition_met", "true"),
+					resource.TestCheckResourceAttrSet("data.elasticsearch_watch_history.test", "executions.0.triggered_time"),
+				),
+			},
+		},
+	})
+}
+
+// testExecuteElasticsearchWatch run the watch and record the execution on watch history
+func testExecuteElasticsearchWatch(t *testing.T, id string) {
+	meta := testAccProvider.Meta()
+	if meta == nil {
+		t.Fatal("Provider is not configured")
+	}
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Watcher.ExecuteWatch(
+		client.API.Watcher.ExecuteWatch.WithWatchID(id),
+		client.API.Watcher.ExecuteWatch.WithBody(strings.NewReader(`{"record_execution": true}`)),
+		client.API.Watcher.ExecuteWatch.WithContext(context.Background()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		t.Fatalf("Error when execute watch %s: %s", id, res.String())
+	}
+
+	// The watch history is written asynchronously
+	time.Sleep(2 * time.Second)
+	resRefresh, err := client.API.Indices.Refresh(
+		client.API.Indices.Refresh.WithIndex(WatchHistoryIndexPattern),
+		client.API.Indices.Refresh.WithExpandWildcards("open,hidden"),
+		client.API.Indices.Refresh.WithContext(context.Background()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resRefresh.Body.Close()
+	if resRefresh.IsError() {
+		t.Fatalf("Error when refresh watch history: %s", resRefresh.String())
+	}
+}
+
+var testElasticsearchWatchHistoryWatch = `
+resource "elasticsearch_watcher" "test" {
+  name    = "terraform-test-history"
+  active  = false
+  trigger = <<EOF
+{
+  "schedule" : { "interval" : "1h" }
+}
+EOF
+  input = <<EOF
+{
+  "simple" : { "hits" : { "total" : 1 } }
+}
+EOF
+  condition = <<EOF
+{
+  "compare" : { "ctx.payload.hits.total" : { "gt" : 0 } }
+}
+EOF
+  actions = <<EOF
+{
+  "log" : {
+    "logging" : {
+      "text" : "{{ctx.payload.hits.total}} hits"
+    }
+  }
+}
+EOF
+}
+`
+
+var testElasticsearchWatchHistoryDataSource = testElasticsearchWatchHistoryWatch + `
+data "elasticsearch_watch_history" "test" {
+  watch_id = elasticsearch_watcher.test.id
+  size     = 1
+}
+`
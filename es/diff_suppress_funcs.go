@@ -4,10 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 
 	eshandler "github.com/disaster37/es-handler/v8"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/olivere/elastic/v7"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -99,19 +99,83 @@ func diffSuppressTransform(k, old, new string, d *schema.ResourceData) bool {
 
 // diffSuppressIngestPipeline permit to compare ingest pipeline in current state vs from API
 func diffSuppressIngestPipeline(k, old, new string, d *schema.ResourceData) bool {
-	oo := &elastic.IngestGetPipeline{}
-	no := &elastic.IngestGetPipeline{}
+	oo := &IngestPipeline{}
+	no := &IngestPipeline{}
 
 	if err := json.Unmarshal([]byte(old), &oo); err != nil {
-		fmt.Printf("[ERR] Error when converting to IngestGetPipeline on old object: %s", err.Error())
-		log.Errorf("Error when converting to IngestGetPipeline on old object: %s\n%s", err.Error(), old)
+		fmt.Printf("[ERR] Error when converting to IngestPipeline on old object: %s", err.Error())
+		log.Errorf("Error when converting to IngestPipeline on old object: %s\n%s", err.Error(), old)
 		return false
 	}
 	if err := json.Unmarshal([]byte(new), &no); err != nil {
-		fmt.Printf("[ERR] Error when converting to IngestGetPipeline on new object: %s", err.Error())
-		log.Errorf("Error when converting to IngestGetPipeline on new object: %s\n%s", err.Error(), new)
+		fmt.Printf("[ERR] Error when converting to IngestPipeline on new object: %s", err.Error())
+		log.Errorf("Error when converting to IngestPipeline on new object: %s\n%s", err.Error(), new)
 		return false
 	}
+	normalizeIngestPipeline(oo)
+	normalizeIngestPipeline(no)
 
 	return reflect.DeepEqual(no, oo)
 }
+
+// diffSuppressIndexSettings permit to compare index settings in flat or nested format
+// Elasticsearch return the settings nested, with index prefix and values as string
+func diffSuppressIndexSettings(k, old, new string, d *schema.ResourceData) bool {
+	oo, err := normalizeIndexSettings(old)
+	if err != nil {
+		fmt.Printf("[ERR] Error when converting old index settings: %s", err.Error())
+		log.Errorf("Error when converting old index settings: %s\n%s", err.Error(), old)
+		return false
+	}
+	no, err := normalizeIndexSettings(new)
+	if err != nil {
+		fmt.Printf("[ERR] Error when converting new index settings: %s", err.Error())
+		log.Errorf("Error when converting new index settings: %s\n%s", err.Error(), new)
+		return false
+	}
+
+	return reflect.DeepEqual(no, oo)
+}
+
+// normalizeIndexSettings convert index settings to flat settings with index prefix and values as string
+func normalizeIndexSettings(raw string) (settings map[string]any, err error) {
+	nested, err := convertRawJsonTopMapString(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	flatten := map[string]any{}
+	flattenIndexSettings("", nested, flatten)
+	settings = make(map[string]any, len(flatten))
+	for key, value := range flatten {
+		if !strings.HasPrefix(key, "index.") {
+			key = "index." + key
+		}
+		settings[key] = value
+	}
+
+	return settings, nil
+}
+
+// flattenIndexSettings flatten the nested settings in result
+func flattenIndexSettings(prefix string, nested map[string]any, result map[string]any) {
+	for key, value := range nested {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			flattenIndexSettings(key, v, result)
+		case []any:
+			values := make([]any, 0, len(v))
+			for _, item := range v {
+				values = append(values, fmt.Sprintf("%v", item))
+			}
+			result[key] = values
+		case nil:
+			result[key] = nil
+		default:
+			result[key] = fmt.Sprintf("%v", v)
+		}
+	}
+}
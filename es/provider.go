@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/coreos/go-semver/semver"
@@ -27,6 +28,16 @@ import (
 var logEntry *logrus.Entry
 var esHandler eshandler.ElasticsearchHandler
 
+// providerMeta is the meta given to resources, it's the Elasticsearch handler with the caches of the provider
+// Each provider configuration has its own caches, because the aliases can target different clusters
+type providerMeta struct {
+	eshandler.ElasticsearchHandler
+
+	builtinPrivileges       *SecurityBuiltinPrivilegesResponse
+	builtinPrivilegesLoaded bool
+	builtinPrivilegesLock   sync.Mutex
+}
+
 // Provider permiit to init the terraform provider
 func Provider() *schema.Provider {
 	return &schema.Provider{
@@ -48,6 +59,7 @@ func Provider() *schema.Provider {
 				Type:        schema.TypeString,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("ELASTICSEARCH_PASSWORD", nil),
+				Sensitive:   true,
 				Description: "Password to use to connect to elasticsearch using basic auth",
 			},
 			"cacert_file": {
@@ -84,6 +96,8 @@ func Provider() *schema.Provider {
 
 		ResourcesMap: map[string]*schema.Resource{
 			"elasticsearch_index_lifecycle_policy":    resourceElasticsearchIndexLifecyclePolicy(),
+			"elasticsearch_ilm_retry":                 resourceElasticsearchILMRetry(),
+			"elasticsearch_ilm_policy_attachment":     resourceElasticsearchILMPolicyAttachment(),
 			"elasticsearch_index_template_legacy":     resourceElasticsearchIndexTemplateLegacy(),
 			"elasticsearch_index_template":            resourceElasticsearchIndexTemplate(),
 			"elasticsearch_index_component_template":  resourceElasticsearchIndexComponentTemplate(),
@@ -93,12 +107,24 @@ func Provider() *schema.Provider {
 			"elasticsearch_license":                   resourceElasticsearchLicense(),
 			"elasticsearch_snapshot_repository":       resourceElasticsearchSnapshotRepository(),
 			"elasticsearch_snapshot_lifecycle_policy": resourceElasticsearchSnapshotLifecyclePolicy(),
+			"elasticsearch_lifecycle_operation_mode":  resourceElasticsearchLifecycleOperationMode(),
 			"elasticsearch_watcher":                   resourceElasticsearchWatcher(),
+			"elasticsearch_watcher_service":           resourceElasticsearchWatcherService(),
 			"elasticsearch_data_stream":               resourceElasticsearchDataStream(),
+			"elasticsearch_data_stream_rollover":      resourceElasticsearchDataStreamRollover(),
 			"elasticsearch_transform":                 resourceElasticsearchTransform(),
 			"elasticsearch_ingest_pipeline":           resourceElasticsearchIngestPipeline(),
 		},
 
+		DataSourcesMap: map[string]*schema.Resource{
+			"elasticsearch_ilm_explain":                     dataSourceElasticsearchILMExplain(),
+			"elasticsearch_watch_execution":                 dataSourceElasticsearchWatchExecution(),
+			"elasticsearch_watch_history":                   dataSourceElasticsearchWatchHistory(),
+			"elasticsearch_ingest_pipeline_simulation":      dataSourceElasticsearchIngestPipelineSimulation(),
+			"elasticsearch_index_template_simulation":       dataSourceElasticsearchIndexTemplateSimulation(),
+			"elasticsearch_index_template_legacy_migration": dataSourceElasticsearchIndexTemplateLegacyMigration(),
+		},
+
 		ConfigureContextFunc: providerConfigure,
 	}
 }
@@ -199,7 +225,9 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		return nil, diag.FromErr(errors.New("Elasticsearch is older than 8.0.0"))
 	}
 
-	return client, nil
+	return &providerMeta{
+		ElasticsearchHandler: client,
+	}, nil
 }
 
 // If the argument is a path, Read loads it and returns the contents,
@@ -2,9 +2,14 @@ package es
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"testing"
 
+	eshandler "github.com/disaster37/es-handler/v8"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/sirupsen/logrus"
@@ -50,3 +55,38 @@ func testAccPreCheck(t *testing.T) {
 	}
 
 }
+
+// testAccPreCheckVersion skip the test if Elasticsearch is older than major.minor
+func testAccPreCheckVersion(t *testing.T, major int, minor int) {
+	testAccPreCheck(t)
+
+	client := testAccProvider.Meta().(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Info(client.API.Info.WithContext(context.Background()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		t.Fatalf("Error when get Elasticsearch version: %s", res.String())
+	}
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := struct {
+		Version struct {
+			Number string `json:"number"`
+		} `json:"version"`
+	}{}
+	if err = json.Unmarshal(b, &info); err != nil {
+		t.Fatal(err)
+	}
+
+	var currentMajor, currentMinor int
+	if _, err = fmt.Sscanf(info.Version.Number, "%d.%d", &currentMajor, &currentMinor); err != nil {
+		t.Fatal(err)
+	}
+	if currentMajor < major || (currentMajor == major && currentMinor < minor) {
+		t.Skipf("Elasticsearch %s is older than %d.%d", info.Version.Number, major, minor)
+	}
+}
@@ -6,18 +6,43 @@
 package es
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+
 	"github.com/disaster37/es-handler/v8"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
-	"io/ioutil"
 )
 
 type IndicesGetDataStreamResponse struct {
-	DataStreams []interface{} `json:"data_streams,omitempty"`
+	DataStreams []IndicesGetDataStream `json:"data_streams,omitempty"`
+}
+
+type IndicesGetDataStream struct {
+	Name       string                       `json:"name"`
+	Generation int64                        `json:"generation,omitempty"`
+	Status     string                       `json:"status,omitempty"`
+	Template   string                       `json:"template,omitempty"`
+	IlmPolicy  string                       `json:"ilm_policy,omitempty"`
+	Indices    []IndicesGetDataStreamIndice `json:"indices,omitempty"`
+}
+
+type IndicesGetDataStreamIndice struct {
+	IndexName string `json:"index_name"`
+	IndexUUID string `json:"index_uuid,omitempty"`
+}
+
+type IndicesModifyDataStreamRequest struct {
+	Actions []map[string]IndicesModifyDataStreamAction `json:"actions"`
+}
+
+type IndicesModifyDataStreamAction struct {
+	DataStream string `json:"data_stream"`
+	Index      string `json:"index"`
 }
 
 // resourceElasticsearchDataStream handle the data stream API call
@@ -25,6 +50,7 @@ func resourceElasticsearchDataStream() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceElasticsearchDataStreamCreate,
 		Read:   resourceElasticsearchDataStreamRead,
+		Update: resourceElasticsearchDataStreamUpdate,
 		Delete: resourceElasticsearchDataStreamDelete,
 
 		Importer: &schema.ResourceImporter{
@@ -37,6 +63,26 @@ func resourceElasticsearchDataStream() *schema.Resource {
 				ForceNew: true,
 				Required: true,
 			},
+			"migrate_from_alias": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			"adopted_indices": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"backing_indices": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 		},
 	}
 }
@@ -44,11 +90,35 @@ func resourceElasticsearchDataStream() *schema.Resource {
 // resourceElasticsearchDataStreamCreate create data stream
 func resourceElasticsearchDataStreamCreate(d *schema.ResourceData, meta interface{}) (err error) {
 
-	err = createDataStream(d, meta)
+	if d.Get("migrate_from_alias").(bool) {
+		err = migrateAliasToDataStream(d, meta)
+	} else {
+		err = createDataStream(d, meta)
+	}
 	if err != nil {
 		return err
 	}
 	d.SetId(d.Get("name").(string))
+
+	adoptedIndices := convertArrayInterfaceToArrayString(d.Get("adopted_indices").(*schema.Set).List())
+	if err = modifyDataStreamBackingIndices(d.Id(), adoptedIndices, nil, meta); err != nil {
+		return err
+	}
+
+	return resourceElasticsearchDataStreamRead(d, meta)
+}
+
+// resourceElasticsearchDataStreamUpdate add or remove adopted backing indices
+func resourceElasticsearchDataStreamUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	if d.HasChange("adopted_indices") {
+		o, n := d.GetChange("adopted_indices")
+		addIndices := convertArrayInterfaceToArrayString(n.(*schema.Set).Difference(o.(*schema.Set)).List())
+		removeIndices := convertArrayInterfaceToArrayString(o.(*schema.Set).Difference(n.(*schema.Set)).List())
+		if err = modifyDataStreamBackingIndices(d.Id(), addIndices, removeIndices, meta); err != nil {
+			return err
+		}
+	}
+
 	return resourceElasticsearchDataStreamRead(d, meta)
 }
 
@@ -97,10 +167,33 @@ func resourceElasticsearchDataStreamRead(d *schema.ResourceData, meta interface{
 		return err
 	}
 
-	log.Debugf("Get data stream %s successfully:%+v", id, dataStreamJSON)
+	log.Debugf("Get data stream %s successfully:%s", id, string(dataStreamJSON))
+
+	backingIndices := make([]string, 0, len(dataStream.DataStreams[0].Indices))
+	for _, indice := range dataStream.DataStreams[0].Indices {
+		backingIndices = append(backingIndices, indice.IndexName)
+	}
+
+	// Only keep adopted indices that are always backing indices to detect drift
+	adoptedIndices := make([]string, 0)
+	for _, adoptedIndice := range convertArrayInterfaceToArrayString(d.Get("adopted_indices").(*schema.Set).List()) {
+		for _, indice := range backingIndices {
+			if indice == adoptedIndice {
+				adoptedIndices = append(adoptedIndices, adoptedIndice)
+				break
+			}
+		}
+	}
+
 	if err = d.Set("name", d.Id()); err != nil {
 		return err
 	}
+	if err = d.Set("backing_indices", backingIndices); err != nil {
+		return err
+	}
+	if err = d.Set("adopted_indices", adoptedIndices); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -160,3 +253,78 @@ func createDataStream(d *schema.ResourceData, meta interface{}) (err error) {
 
 	return nil
 }
+
+// migrateAliasToDataStream convert an existing alias to data stream
+func migrateAliasToDataStream(d *schema.ResourceData, meta interface{}) (err error) {
+	name := d.Get("name").(string)
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Indices.MigrateToDataStream(
+		name,
+		client.API.Indices.MigrateToDataStream.WithContext(context.Background()),
+		client.API.Indices.MigrateToDataStream.WithPretty(),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return errors.Errorf("Error when migrate alias %s to data stream: %s", name, res.String())
+	}
+
+	return nil
+}
+
+// modifyDataStreamBackingIndices add and remove backing indices on data stream
+func modifyDataStreamBackingIndices(name string, addIndices []string, removeIndices []string, meta interface{}) (err error) {
+	if len(addIndices) == 0 && len(removeIndices) == 0 {
+		return nil
+	}
+
+	data := &IndicesModifyDataStreamRequest{
+		Actions: make([]map[string]IndicesModifyDataStreamAction, 0, len(addIndices)+len(removeIndices)),
+	}
+	for _, indice := range removeIndices {
+		data.Actions = append(data.Actions, map[string]IndicesModifyDataStreamAction{
+			"remove_backing_index": {
+				DataStream: name,
+				Index:      indice,
+			},
+		})
+	}
+	for _, indice := range addIndices {
+		data.Actions = append(data.Actions, map[string]IndicesModifyDataStreamAction{
+			"add_backing_index": {
+				DataStream: name,
+				Index:      indice,
+			},
+		})
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Indices.ModifyDataStream(
+		bytes.NewReader(b),
+		client.API.Indices.ModifyDataStream.WithContext(context.Background()),
+		client.API.Indices.ModifyDataStream.WithPretty(),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return errors.Errorf("Error when modify backing indices of data stream %s: %s\ndata: %s", name, res.String(), string(b))
+	}
+
+	return nil
+}
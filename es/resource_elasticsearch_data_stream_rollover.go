@@ -0,0 +1,212 @@
+// Rollover data stream in Elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-rollover-index.html
+// Supported version:
+//  - v8
+
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// IndicesRolloverResponse is the rollover API response
+type IndicesRolloverResponse struct {
+	Acknowledged bool   `json:"acknowledged"`
+	OldIndex     string `json:"old_index"`
+	NewIndex     string `json:"new_index"`
+	RolledOver   bool   `json:"rolled_over"`
+	DryRun       bool   `json:"dry_run"`
+	Lazy         bool   `json:"lazy"`
+}
+
+// resourceElasticsearchDataStreamRollover handle the data stream rollover API call
+// It's an action resource: the rollover is run each time the resource is created, so when triggers change
+func resourceElasticsearchDataStreamRollover() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchDataStreamRolloverCreate,
+		Read:   resourceElasticsearchDataStreamRolloverRead,
+		Delete: resourceElasticsearchDataStreamRolloverDelete,
+
+		Schema: map[string]*schema.Schema{
+			"data_stream": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Required: true,
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"conditions": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+				ConflictsWith:    []string{"lazy"},
+			},
+			"lazy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			"old_index": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"new_index": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"rolled_over": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceElasticsearchDataStreamRolloverCreate rollover the data stream
+func resourceElasticsearchDataStreamRolloverCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	name := d.Get("data_stream").(string)
+
+	rollover, err := rolloverDataStream(d, meta)
+	if err != nil {
+		return err
+	}
+	d.SetId(name)
+
+	if err = d.Set("old_index", rollover.OldIndex); err != nil {
+		return err
+	}
+	if err = d.Set("new_index", rollover.NewIndex); err != nil {
+		return err
+	}
+	if err = d.Set("rolled_over", rollover.RolledOver); err != nil {
+		return err
+	}
+
+	log.Infof("Rollover data stream %s successfully", name)
+
+	return resourceElasticsearchDataStreamRolloverRead(d, meta)
+}
+
+// resourceElasticsearchDataStreamRolloverRead check the data stream always exist
+func resourceElasticsearchDataStreamRolloverRead(d *schema.ResourceData, meta interface{}) (err error) {
+	id := d.Id()
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Indices.GetDataStream(
+		client.API.Indices.GetDataStream.WithName(id),
+		client.API.Indices.GetDataStream.WithContext(context.Background()),
+		client.API.Indices.GetDataStream.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			fmt.Printf("[WARN] Data stream %s not found - removing rollover from state", id)
+			log.Warnf("Data stream %s not found - removing rollover from state", id)
+			d.SetId("")
+			return nil
+		}
+		return errors.Errorf("Error when get data stream %s: %s", id, res.String())
+	}
+
+	if err = d.Set("data_stream", id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resourceElasticsearchDataStreamRolloverDelete only remove the rollover from state
+func resourceElasticsearchDataStreamRolloverDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	d.SetId("")
+	return nil
+}
+
+// rolloverDataStream run the rollover on data stream
+func rolloverDataStream(d *schema.ResourceData, meta interface{}) (rollover *IndicesRolloverResponse, err error) {
+	name := d.Get("data_stream").(string)
+	conditions := d.Get("conditions").(string)
+	lazy := d.Get("lazy").(bool)
+
+	var body io.Reader
+	if conditions != "" {
+		body = strings.NewReader(fmt.Sprintf(`{"conditions": %s}`, conditions))
+	}
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+
+	var res *esapi.Response
+	if lazy {
+		// The lazy parameter is not yet provided by the client
+		req, err := http.NewRequest(http.MethodPost, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		req.URL = &url.URL{
+			Path:     fmt.Sprintf("/%s/_rollover", name),
+			RawQuery: "lazy=true",
+		}
+		req = req.WithContext(context.Background())
+		httpRes, err := client.Perform(req)
+		if err != nil {
+			return nil, err
+		}
+		res = &esapi.Response{
+			StatusCode: httpRes.StatusCode,
+			Header:     httpRes.Header,
+			Body:       httpRes.Body,
+		}
+	} else {
+		res, err = client.API.Indices.Rollover(
+			name,
+			client.API.Indices.Rollover.WithBody(body),
+			client.API.Indices.Rollover.WithContext(context.Background()),
+			client.API.Indices.Rollover.WithPretty(),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, errors.Errorf("Error when rollover data stream %s: %s", name, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	rollover = &IndicesRolloverResponse{}
+	if err = json.Unmarshal(b, rollover); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Rollover data stream %s: %s", name, string(b))
+
+	return rollover, nil
+}
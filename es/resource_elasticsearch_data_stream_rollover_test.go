@@ -0,0 +1,141 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchDataStreamRollover(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchDataStreamRollover,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchDataStreamRolloverExists("elasticsearch_data_stream_rollover.test"),
+					resource.TestCheckResourceAttr("elasticsearch_data_stream_rollover.test", "rolled_over", "true"),
+					resource.TestCheckResourceAttrSet("elasticsearch_data_stream_rollover.test", "new_index"),
+				),
+			},
+			{
+				Config: testElasticsearchDataStreamRolloverUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchDataStreamRolloverExists("elasticsearch_data_stream_rollover.test"),
+					resource.TestCheckResourceAttr("elasticsearch_data_stream_rollover.test", "rolled_over", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testCheckElasticsearchDataStreamRolloverExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No data stream rollover ID is set")
+		}
+
+		meta := testAccProvider.Meta()
+
+		client := meta.(eshandler.ElasticsearchHandler).Client()
+		res, err := client.API.Indices.GetDataStream(
+			client.API.Indices.GetDataStream.WithName(rs.Primary.ID),
+			client.API.Indices.GetDataStream.WithContext(context.Background()),
+			client.API.Indices.GetDataStream.WithPretty(),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return errors.Errorf("Error when get data stream %s: %s", rs.Primary.ID, res.String())
+		}
+
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		dataStream := IndicesGetDataStreamResponse{}
+		if err := json.Unmarshal(b, &dataStream); err != nil {
+			return err
+		}
+		if len(dataStream.DataStreams) == 0 {
+			return errors.Errorf("Data stream %s not found", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+var testElasticsearchDataStreamRollover = `
+resource "elasticsearch_index_template" "test-data-stream-rollover" {
+  name 		= "test-data-stream-rollover"
+  template 	= <<EOF
+{
+	"index_patterns": ["terraform-test-rollover"],
+	"data_stream": {},
+	"priority": 3
+}
+EOF
+}
+
+resource "elasticsearch_data_stream" "test" {
+  name 		= "terraform-test-rollover"
+
+	depends_on = [ elasticsearch_index_template.test-data-stream-rollover ]
+}
+
+resource "elasticsearch_data_stream_rollover" "test" {
+  data_stream = elasticsearch_data_stream.test.name
+  triggers    = {
+    template = elasticsearch_index_template.test-data-stream-rollover.template
+  }
+}
+`
+
+var testElasticsearchDataStreamRolloverUpdate = `
+resource "elasticsearch_index_template" "test-data-stream-rollover" {
+  name 		= "test-data-stream-rollover"
+  template 	= <<EOF
+{
+	"index_patterns": ["terraform-test-rollover"],
+	"data_stream": {},
+	"priority": 3
+}
+EOF
+}
+
+resource "elasticsearch_data_stream" "test" {
+  name 		= "terraform-test-rollover"
+
+	depends_on = [ elasticsearch_index_template.test-data-stream-rollover ]
+}
+
+resource "elasticsearch_data_stream_rollover" "test" {
+  data_stream = elasticsearch_data_stream.test.name
+  conditions  = <<EOF
+{
+	"max_docs": 1000
+}
+EOF
+  triggers    = {
+    template = elasticsearch_index_template.test-data-stream-rollover.template
+  }
+}
+`
@@ -3,6 +3,7 @@ package es
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	eshandler "github.com/disaster37/es-handler/v8"
@@ -28,20 +29,76 @@ func TestAccElasticsearchDataStream(t *testing.T) {
 				),
 			},
 			{
+				PreConfig: func() {
+					testCreateElasticsearchIndex(t, "terraform-test-adopted", `{"mappings": {"properties": {"@timestamp": {"type": "date"}}}}`)
+				},
 				Config: testElasticsearchDataStreamUpdate,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckElasticsearchDataStreamExists("elasticsearch_data_stream.test"),
+					resource.TestCheckResourceAttr("elasticsearch_data_stream.test", "adopted_indices.#", "1"),
+					resource.TestCheckResourceAttr("elasticsearch_data_stream.test", "backing_indices.#", "2"),
 				),
 			},
 			{
-				ResourceName:      "elasticsearch_data_stream.test",
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            "elasticsearch_data_stream.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"adopted_indices", "migrate_from_alias"},
 			},
 		},
 	})
 }
 
+func TestAccElasticsearchDataStreamMigrateFromAlias(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchDataStreamDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchDataStreamMigrateTemplate,
+			},
+			{
+				PreConfig: func() {
+					testCreateElasticsearchIndex(t, "terraform-test-migrate-000001", `{
+	"mappings": {"properties": {"@timestamp": {"type": "date"}}},
+	"aliases": {"terraform-test-migrate": {"is_write_index": true}}
+}`)
+				},
+				Config: testElasticsearchDataStreamMigrate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchDataStreamExists("elasticsearch_data_stream.test"),
+					resource.TestCheckResourceAttr("elasticsearch_data_stream.test", "backing_indices.0", "terraform-test-migrate-000001"),
+				),
+			},
+		},
+	})
+}
+
+func testCreateElasticsearchIndex(t *testing.T, name string, body string) {
+	meta := testAccProvider.Meta()
+	if meta == nil {
+		t.Fatal("Provider is not configured")
+	}
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Indices.Create(
+		name,
+		client.API.Indices.Create.WithBody(strings.NewReader(body)),
+		client.API.Indices.Create.WithContext(context.Background()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		t.Fatalf("Error when create index %s: %s", name, res.String())
+	}
+}
+
 func testCheckElasticsearchDataStreamExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[name]
@@ -139,6 +196,40 @@ EOF
 
 resource "elasticsearch_data_stream" "test" {
   name 		= "terraform-test"
+  adopted_indices = ["terraform-test-adopted"]
+
+	depends_on = [ elasticsearch_index_template.test-data-stream ]
+}
+`
+
+var testElasticsearchDataStreamMigrateTemplate = `
+resource "elasticsearch_index_template" "test-data-stream" {
+  name 		= "test-data-stream-migrate"
+  template 	= <<EOF
+{
+	"index_patterns": ["terraform-test-migrate"],
+	"data_stream": {},
+	"priority": 4
+}
+EOF
+}
+`
+
+var testElasticsearchDataStreamMigrate = `
+resource "elasticsearch_index_template" "test-data-stream" {
+  name 		= "test-data-stream-migrate"
+  template 	= <<EOF
+{
+	"index_patterns": ["terraform-test-migrate"],
+	"data_stream": {},
+	"priority": 4
+}
+EOF
+}
+
+resource "elasticsearch_data_stream" "test" {
+  name 		= "terraform-test-migrate"
+  migrate_from_alias = true
 
 	depends_on = [ elasticsearch_index_template.test-data-stream ]
 }
@@ -0,0 +1,296 @@
+// Attach index lifecycle policy on existing indices in Elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/ilm-remove-policy.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// IndicesGetSettingsResponse is the get settings API response with flat settings
+type IndicesGetSettingsResponse map[string]struct {
+	Settings map[string]interface{} `json:"settings"`
+}
+
+// resourceElasticsearchILMPolicyAttachment handle the index settings API call to attach ILM policy
+func resourceElasticsearchILMPolicyAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchILMPolicyAttachmentCreate,
+		Read:   resourceElasticsearchILMPolicyAttachmentRead,
+		Update: resourceElasticsearchILMPolicyAttachmentUpdate,
+		Delete: resourceElasticsearchILMPolicyAttachmentDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		CustomizeDiff: resourceElasticsearchILMPolicyAttachmentCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"policy": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"rollover_alias": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"indices": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"mismatched_indices": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// resourceElasticsearchILMPolicyAttachmentCreate attach the policy on indices
+func resourceElasticsearchILMPolicyAttachmentCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	index := d.Get("index").(string)
+
+	if err = attachIndexLifecyclePolicy(d, meta); err != nil {
+		return err
+	}
+	d.SetId(index)
+
+	return resourceElasticsearchILMPolicyAttachmentRead(d, meta)
+}
+
+// resourceElasticsearchILMPolicyAttachmentUpdate attach the new policy on indices
+func resourceElasticsearchILMPolicyAttachmentUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	if err = attachIndexLifecyclePolicy(d, meta); err != nil {
+		return err
+	}
+
+	return resourceElasticsearchILMPolicyAttachmentRead(d, meta)
+}
+
+// resourceElasticsearchILMPolicyAttachmentCustomizeDiff mark matched indices as unknown when the policy will be attached
+// The policy is attached again when some indices have not the expected policy or rollover alias
+func resourceElasticsearchILMPolicyAttachmentCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) (err error) {
+	if d.Id() == "" {
+		return nil
+	}
+	if d.HasChanges("policy", "rollover_alias") || len(d.Get("mismatched_indices").([]interface{})) > 0 {
+		if err = d.SetNewComputed("indices"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("mismatched_indices")
+	}
+
+	return nil
+}
+
+// resourceElasticsearchILMPolicyAttachmentRead read the policy attached on indices
+// The indices that have not the expected policy or rollover alias are reported on mismatched_indices
+func resourceElasticsearchILMPolicyAttachmentRead(d *schema.ResourceData, meta interface{}) (err error) {
+	id := d.Id()
+	policy := d.Get("policy").(string)
+	rolloverAlias := d.Get("rollover_alias").(string)
+
+	settings, err := getIndexLifecycleSettings(id, meta)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(settings))
+	for index := range settings {
+		names = append(names, index)
+	}
+	sort.Strings(names)
+
+	// On import, take the policy of the first index
+	if policy == "" && len(names) > 0 {
+		policy, _ = settings[names[0]].Settings["index.lifecycle.name"].(string)
+		rolloverAlias, _ = settings[names[0]].Settings["index.lifecycle.rollover_alias"].(string)
+	}
+
+	indices := make([]string, 0, len(names))
+	mismatchedIndices := make([]string, 0)
+	for _, index := range names {
+		indexPolicy, _ := settings[index].Settings["index.lifecycle.name"].(string)
+		indexRolloverAlias, _ := settings[index].Settings["index.lifecycle.rollover_alias"].(string)
+
+		if indexPolicy == policy && indexRolloverAlias == rolloverAlias {
+			indices = append(indices, index)
+			continue
+		}
+
+		log.Debugf("Index %s has policy %s and rollover alias %s instead of %s and %s", index, indexPolicy, indexRolloverAlias, policy, rolloverAlias)
+		mismatchedIndices = append(mismatchedIndices, index)
+	}
+
+	if err = d.Set("index", id); err != nil {
+		return err
+	}
+	if err = d.Set("policy", policy); err != nil {
+		return err
+	}
+	if err = d.Set("rollover_alias", rolloverAlias); err != nil {
+		return err
+	}
+	if err = d.Set("indices", indices); err != nil {
+		return err
+	}
+	if err = d.Set("mismatched_indices", mismatchedIndices); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resourceElasticsearchILMPolicyAttachmentDelete remove the policy from indices
+// Only the indices that have the policy of this resource are changed, the other indices that match the pattern are kept as is
+func resourceElasticsearchILMPolicyAttachmentDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	id := d.Id()
+	policy := d.Get("policy").(string)
+
+	settings, err := getIndexLifecycleSettings(id, meta)
+	if err != nil {
+		return err
+	}
+
+	indices := make([]string, 0, len(settings))
+	for index, setting := range settings {
+		if indexPolicy, _ := setting.Settings["index.lifecycle.name"].(string); indexPolicy == policy {
+			indices = append(indices, index)
+		}
+	}
+	sort.Strings(indices)
+
+	// The indices are sent by batch to not exceed the max URL length
+	for len(indices) > 0 {
+		batch := make([]string, 0)
+		size := 0
+		for len(indices) > 0 && (len(batch) == 0 || size+len(indices[0]) < 2048) {
+			size += len(indices[0]) + 1
+			batch = append(batch, indices[0])
+			indices = indices[1:]
+		}
+		if err = removeIndexLifecyclePolicy(strings.Join(batch, ","), meta); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// getIndexLifecycleSettings return the lifecycle settings of indices that match the pattern
+func getIndexLifecycleSettings(index string, meta interface{}) (settings IndicesGetSettingsResponse, err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Indices.GetSettings(
+		client.API.Indices.GetSettings.WithIndex(index),
+		client.API.Indices.GetSettings.WithName("index.lifecycle.name", "index.lifecycle.rollover_alias"),
+		client.API.Indices.GetSettings.WithFlatSettings(true),
+		client.API.Indices.GetSettings.WithContext(context.Background()),
+		client.API.Indices.GetSettings.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	settings = IndicesGetSettingsResponse{}
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return settings, nil
+		}
+		return nil, errors.Errorf("Error when get settings of %s: %s", index, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// removeIndexLifecyclePolicy remove the lifecycle policy from indices
+func removeIndexLifecyclePolicy(index string, meta interface{}) (err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.ILM.RemovePolicy(
+		index,
+		client.API.ILM.RemovePolicy.WithContext(context.Background()),
+		client.API.ILM.RemovePolicy.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() && res.StatusCode != 404 {
+		return errors.Errorf("Error when remove lifecycle policy from %s: %s", index, res.String())
+	}
+
+	log.Infof("Remove lifecycle policy from %s successfully", index)
+
+	return nil
+}
+
+// attachIndexLifecyclePolicy set the lifecycle settings on indices
+func attachIndexLifecyclePolicy(d *schema.ResourceData, meta interface{}) (err error) {
+	index := d.Get("index").(string)
+	policy := d.Get("policy").(string)
+	rolloverAlias := d.Get("rollover_alias").(string)
+
+	settings := map[string]interface{}{
+		"index.lifecycle.name":           policy,
+		"index.lifecycle.rollover_alias": nil,
+	}
+	if rolloverAlias != "" {
+		settings["index.lifecycle.rollover_alias"] = rolloverAlias
+	}
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Indices.PutSettings(
+		bytes.NewReader(b),
+		client.API.Indices.PutSettings.WithIndex(index),
+		client.API.Indices.PutSettings.WithContext(context.Background()),
+		client.API.Indices.PutSettings.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when attach lifecycle policy %s on %s: %s", policy, index, res.String())
+	}
+
+	log.Infof("Attach lifecycle policy %s on %s successfully", policy, index)
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchILMPolicyAttachment(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchILMPolicyAttachmentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchILMPolicyAttachmentPolicy,
+			},
+			{
+				PreConfig: func() {
+					testCreateElasticsearchIndex(t, "terraform-test-ilm-attachment-000001", `{}`)
+					testCreateElasticsearchIndex(t, "terraform-test-ilm-attachment-000002", `{}`)
+				},
+				Config: testElasticsearchILMPolicyAttachment,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchILMPolicyAttachmentExists("elasticsearch_ilm_policy_attachment.test"),
+					resource.TestCheckResourceAttr("elasticsearch_ilm_policy_attachment.test", "indices.#", "2"),
+				),
+			},
+			{
+				Config: testElasticsearchILMPolicyAttachmentUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchILMPolicyAttachmentExists("elasticsearch_ilm_policy_attachment.test"),
+					resource.TestCheckResourceAttr("elasticsearch_ilm_policy_attachment.test", "rollover_alias", "terraform-test-ilm-attachment"),
+				),
+			},
+			{
+				PreConfig: func() {
+					testCreateElasticsearchIndex(t, "terraform-test-ilm-attachment-000003", `{}`)
+				},
+				Config: testElasticsearchILMPolicyAttachmentUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchILMPolicyAttachmentExists("elasticsearch_ilm_policy_attachment.test"),
+					resource.TestCheckResourceAttr("elasticsearch_ilm_policy_attachment.test", "indices.#", "3"),
+					resource.TestCheckResourceAttr("elasticsearch_ilm_policy_attachment.test", "mismatched_indices.#", "0"),
+				),
+			},
+			{
+				ResourceName:      "elasticsearch_ilm_policy_attachment.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testCheckElasticsearchILMPolicyAttachmentExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ILM policy attachment ID is set")
+		}
+
+		settings, err := testGetElasticsearchIndexSettings(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		for index, setting := range settings {
+			if setting.Settings["index.lifecycle.name"] != rs.Primary.Attributes["policy"] {
+				return errors.Errorf("Index %s has not the policy %s", index, rs.Primary.Attributes["policy"])
+			}
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchILMPolicyAttachmentDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_ilm_policy_attachment" {
+			continue
+		}
+
+		settings, err := testGetElasticsearchIndexSettings(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		for index, setting := range settings {
+			if setting.Settings["index.lifecycle.name"] != nil {
+				return fmt.Errorf("Index %s still has lifecycle policy", index)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testGetElasticsearchIndexSettings(index string) (settings IndicesGetSettingsResponse, err error) {
+	meta := testAccProvider.Meta()
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Indices.GetSettings(
+		client.API.Indices.GetSettings.WithIndex(index),
+		client.API.Indices.GetSettings.WithFlatSettings(true),
+		client.API.Indices.GetSettings.WithContext(context.Background()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, errors.Errorf("Error when get settings of %s: %s", index, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	settings = IndicesGetSettingsResponse{}
+	if err = json.Unmarshal(b, &settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+var testElasticsearchILMPolicyAttachmentPolicy = `
+resource "elasticsearch_index_lifecycle_policy" "test" {
+  name = "terraform-test-ilm-attachment"
+  warm {
+    min_age  = "10d"
+    readonly = true
+  }
+}
+`
+
+var testElasticsearchILMPolicyAttachment = testElasticsearchILMPolicyAttachmentPolicy + `
+resource "elasticsearch_ilm_policy_attachment" "test" {
+  index  = "terraform-test-ilm-attachment-*"
+  policy = elasticsearch_index_lifecycle_policy.test.name
+}
+`
+
+var testElasticsearchILMPolicyAttachmentUpdate = testElasticsearchILMPolicyAttachmentPolicy + `
+resource "elasticsearch_ilm_policy_attachment" "test" {
+  index          = "terraform-test-ilm-attachment-*"
+  policy         = elasticsearch_index_lifecycle_policy.test.name
+  rollover_alias = "terraform-test-ilm-attachment"
+}
+`
@@ -0,0 +1,224 @@
+// Retry failed index lifecycle steps or move indices to another step in Elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/ilm-retry-policy.html
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ilm-move-to-step.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ILMMoveToStepRequest is the move to step API body
+type ILMMoveToStepRequest struct {
+	CurrentStep ILMStepKey `json:"current_step"`
+	NextStep    ILMStepKey `json:"next_step"`
+}
+
+type ILMStepKey struct {
+	Phase  string `json:"phase"`
+	Action string `json:"action,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// resourceElasticsearchILMRetry handle the retry and move to step API call
+// It's an action resource: the retry is run each time the resource is created, so when triggers change
+func resourceElasticsearchILMRetry() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchILMRetryCreate,
+		Read:   resourceElasticsearchILMRetryRead,
+		Delete: resourceElasticsearchILMRetryDelete,
+
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"move_to_step": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"phase": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"action": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"name": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							RequiredWith: []string{"move_to_step.0.action"},
+						},
+					},
+				},
+			},
+			"indices": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// resourceElasticsearchILMRetryCreate retry the failed indices or move them to the next step
+func resourceElasticsearchILMRetryCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	index := d.Get("index").(string)
+
+	var indices []string
+	if raws := d.Get("move_to_step").([]interface{}); len(raws) > 0 && raws[0] != nil {
+		raw := raws[0].(map[string]interface{})
+		nextStep := ILMStepKey{
+			Phase:  raw["phase"].(string),
+			Action: raw["action"].(string),
+			Name:   raw["name"].(string),
+		}
+		indices, err = moveIndexLifecycleToStep(index, nextStep, meta)
+	} else {
+		indices, err = retryIndexLifecycle(index, meta)
+	}
+	if err != nil {
+		return err
+	}
+
+	d.SetId(index)
+	if err = d.Set("indices", indices); err != nil {
+		return err
+	}
+
+	return resourceElasticsearchILMRetryRead(d, meta)
+}
+
+// resourceElasticsearchILMRetryRead do nothing, the retry is a one shot action
+func resourceElasticsearchILMRetryRead(d *schema.ResourceData, meta interface{}) (err error) {
+	return nil
+}
+
+// resourceElasticsearchILMRetryDelete only remove the retry from state
+func resourceElasticsearchILMRetryDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	d.SetId("")
+	return nil
+}
+
+// retryIndexLifecycle retry the failed step on all indices in ERROR that match the pattern
+func retryIndexLifecycle(index string, meta interface{}) (indices []string, err error) {
+	explain, err := explainIndexLifecycle(index, true, true, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	indices = make([]string, 0, len(explain))
+	for _, item := range explain {
+		if err = retryIndexLifecycleOne(item, meta); err != nil {
+			return nil, err
+		}
+		indices = append(indices, item.Index)
+	}
+
+	return indices, nil
+}
+
+// retryIndexLifecycleOne retry the failed step of one index
+func retryIndexLifecycleOne(item ILMExplainLifecycleIndex, meta interface{}) (err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+
+	res, err := client.API.ILM.Retry(
+		item.Index,
+		client.API.ILM.Retry.WithContext(context.Background()),
+		client.API.ILM.Retry.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when retry lifecycle of %s: %s", item.Index, res.String())
+	}
+
+	log.Infof("Retry lifecycle step %s of %s successfully", item.FailedStep, item.Index)
+
+	return nil
+}
+
+// moveIndexLifecycleToStep move all managed indices that match the pattern to the next step
+func moveIndexLifecycleToStep(index string, nextStep ILMStepKey, meta interface{}) (indices []string, err error) {
+	explain, err := explainIndexLifecycle(index, false, true, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	indices = make([]string, 0, len(explain))
+	for _, item := range explain {
+		if err = moveIndexLifecycleToStepOne(item, nextStep, meta); err != nil {
+			return nil, err
+		}
+		indices = append(indices, item.Index)
+	}
+
+	return indices, nil
+}
+
+// moveIndexLifecycleToStepOne move one managed index to the next step
+func moveIndexLifecycleToStepOne(item ILMExplainLifecycleIndex, nextStep ILMStepKey, meta interface{}) (err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+
+	// The current step must match the current step of the index
+	data := &ILMMoveToStepRequest{
+		CurrentStep: ILMStepKey{
+			Phase:  item.Phase,
+			Action: item.Action,
+			Name:   item.Step,
+		},
+		NextStep: nextStep,
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.API.ILM.MoveToStep(
+		item.Index,
+		client.API.ILM.MoveToStep.WithBody(bytes.NewReader(b)),
+		client.API.ILM.MoveToStep.WithContext(context.Background()),
+		client.API.ILM.MoveToStep.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when move lifecycle of %s to step %s: %s", item.Index, string(b), res.String())
+	}
+
+	log.Infof("Move lifecycle of %s to phase %s successfully", item.Index, nextStep.Phase)
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package es
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccElasticsearchILMRetry(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchILMRetryPolicy,
+			},
+			{
+				PreConfig: func() {
+					testCreateElasticsearchIndex(t, "terraform-test-ilm-retry", `{"settings": {"index.lifecycle.name": "terraform-test-ilm-retry"}}`)
+				},
+				Config: testElasticsearchILMRetry,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_ilm_retry.test", "id", "terraform-test-ilm-retry*"),
+					resource.TestCheckResourceAttr("elasticsearch_ilm_retry.test", "indices.#", "0"),
+				),
+			},
+			{
+				Config: testElasticsearchILMRetryMoveToStep,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("elasticsearch_ilm_retry.test", "indices.#", "1"),
+					resource.TestCheckResourceAttr("elasticsearch_ilm_retry.test", "indices.0", "terraform-test-ilm-retry"),
+				),
+			},
+		},
+	})
+}
+
+var testElasticsearchILMRetryPolicy = `
+resource "elasticsearch_index_lifecycle_policy" "test" {
+  name = "terraform-test-ilm-retry"
+  warm {
+    min_age  = "10d"
+    readonly = true
+  }
+}
+`
+
+var testElasticsearchILMRetry = testElasticsearchILMRetryPolicy + `
+resource "elasticsearch_ilm_retry" "test" {
+  index    = "terraform-test-ilm-retry*"
+  triggers = {
+    run = "1"
+  }
+
+  depends_on = [elasticsearch_index_lifecycle_policy.test]
+}
+`
+
+var testElasticsearchILMRetryMoveToStep = testElasticsearchILMRetryPolicy + `
+resource "elasticsearch_ilm_retry" "test" {
+  index    = "terraform-test-ilm-retry*"
+  triggers = {
+    run = "2"
+  }
+  move_to_step {
+    phase = "warm"
+  }
+
+  depends_on = [elasticsearch_index_lifecycle_policy.test]
+}
+`
@@ -6,18 +6,55 @@
 package es
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 
 	eshandler "github.com/disaster37/es-handler/v8"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	olivere "github.com/olivere/elastic/v7"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
+// ComponentTemplate is the component template
+type ComponentTemplate struct {
+	Template *IndexTemplateContent `json:"template,omitempty"`
+	Version  *int64                `json:"version,omitempty"`
+	Meta     map[string]any        `json:"_meta,omitempty"`
+}
+
+// IndexTemplateContent is the template sub section of index template and component template
+type IndexTemplateContent struct {
+	Settings  map[string]any          `json:"settings,omitempty"`
+	Mappings  map[string]any          `json:"mappings,omitempty"`
+	Aliases   map[string]any          `json:"aliases,omitempty"`
+	Lifecycle *IndexTemplateLifecycle `json:"lifecycle,omitempty"`
+}
+
+// IndexTemplateLifecycle is the data stream lifecycle
+type IndexTemplateLifecycle struct {
+	DataRetention string `json:"data_retention,omitempty"`
+	Enabled       *bool  `json:"enabled,omitempty"`
+}
+
+// ComponentTemplateGetResponse is the get component template API response
+type ComponentTemplateGetResponse struct {
+	ComponentTemplates []struct {
+		Name              string            `json:"name"`
+		ComponentTemplate ComponentTemplate `json:"component_template"`
+	} `json:"component_templates"`
+}
+
+// indexTemplateContentFields is the list of typed fields shared by index template and component template
+var indexTemplateContentFields = []string{"settings", "mappings", "aliases", "data_stream_lifecycle", "version", "meta"}
+
 // resourceElasticsearchIndexComponentTemplate handle the index component template API call
 func resourceElasticsearchIndexComponentTemplate() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceElasticsearchIndexComponentTemplateCreate,
 		Update: resourceElasticsearchIndexComponentTemplateUpdate,
 		Read:   resourceElasticsearchIndexComponentTemplateRead,
@@ -34,8 +71,10 @@ func resourceElasticsearchIndexComponentTemplate() *schema.Resource {
 				Required: true,
 			},
 			"template": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: indexTemplateContentFields,
+				AtLeastOneOf:  append([]string{"template"}, indexTemplateContentFields...),
 				DiffSuppressFunc: func(k, oldValue, newValue string, d *schema.ResourceData) bool {
 					var err error
 
@@ -68,6 +107,12 @@ func resourceElasticsearchIndexComponentTemplate() *schema.Resource {
 			},
 		},
 	}
+
+	for key, value := range indexTemplateContentSchema() {
+		r.Schema[key] = value
+	}
+
+	return r
 }
 
 // resourceElasticsearchIndexComponentTemplateCreate create index component template
@@ -94,6 +139,11 @@ func resourceElasticsearchIndexComponentTemplateUpdate(d *schema.ResourceData, m
 func resourceElasticsearchIndexComponentTemplateRead(d *schema.ResourceData, meta interface{}) (err error) {
 	id := d.Id()
 
+	// Use typed fields if raw template is not used
+	if _, useTemplate := d.GetOk("template"); !useTemplate {
+		return readIndexComponentTemplate(d, meta)
+	}
+
 	client := meta.(eshandler.ElasticsearchHandler)
 	ct, err := client.ComponentTemplateGet(id)
 	if err != nil {
@@ -140,6 +190,10 @@ func createIndexComponentTemplate(d *schema.ResourceData, meta interface{}) (err
 	template := d.Get("template").(string)
 	client := meta.(eshandler.ElasticsearchHandler)
 
+	if template == "" {
+		return putIndexComponentTemplate(d, meta)
+	}
+
 	data := &olivere.IndicesGetComponentTemplate{}
 	if err = json.Unmarshal([]byte(template), data); err != nil {
 		return err
@@ -151,3 +205,245 @@ func createIndexComponentTemplate(d *schema.ResourceData, meta interface{}) (err
 
 	return nil
 }
+
+// readIndexComponentTemplate read the component template on typed fields
+func readIndexComponentTemplate(d *schema.ResourceData, meta interface{}) (err error) {
+	id := d.Id()
+
+	ct, err := getIndexComponentTemplate(id, meta)
+	if err != nil {
+		return err
+	}
+	if ct == nil {
+		fmt.Printf("[WARN] Index component template %s not found - removing from state", id)
+		log.Warnf("Index component template %s not found - removing from state", id)
+		d.SetId("")
+		return nil
+	}
+
+	if err = d.Set("name", id); err != nil {
+		return err
+	}
+	if err = flattenIndexTemplateContent(d, ct.Template, ct.Version, ct.Meta); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// putIndexComponentTemplate create or update component template from typed fields
+func putIndexComponentTemplate(d *schema.ResourceData, meta interface{}) (err error) {
+	name := d.Get("name").(string)
+
+	data := &ComponentTemplate{}
+	if data.Template, data.Version, data.Meta, err = expandIndexTemplateContent(d); err != nil {
+		return err
+	}
+	// The template section is mandatory
+	if data.Template == nil {
+		data.Template = &IndexTemplateContent{}
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Cluster.PutComponentTemplate(
+		name,
+		bytes.NewReader(b),
+		client.API.Cluster.PutComponentTemplate.WithContext(context.Background()),
+		client.API.Cluster.PutComponentTemplate.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when add component template %s: %s", name, res.String())
+	}
+
+	log.Infof("Add component template %s successfully", name)
+
+	return nil
+}
+
+// getIndexComponentTemplate return the component template or nil if not exist
+func getIndexComponentTemplate(name string, meta interface{}) (ct *ComponentTemplate, err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Cluster.GetComponentTemplate(
+		client.API.Cluster.GetComponentTemplate.WithName(name),
+		client.API.Cluster.GetComponentTemplate.WithContext(context.Background()),
+		client.API.Cluster.GetComponentTemplate.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get component template %s: %s", name, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	templates := &ComponentTemplateGetResponse{}
+	if err = json.Unmarshal(b, templates); err != nil {
+		return nil, err
+	}
+
+	for _, template := range templates.ComponentTemplates {
+		if template.Name == name {
+			return &template.ComponentTemplate, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// indexTemplateContentSchema return the schema of typed fields shared by index template and component template
+func indexTemplateContentSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"settings": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateFunc:     validation.StringIsJSON,
+			DiffSuppressFunc: diffSuppressIndexSettings,
+		},
+		"mappings": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateFunc:     validation.StringIsJSON,
+			DiffSuppressFunc: suppressEquivalentJSON,
+		},
+		"aliases": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateFunc:     validation.StringIsJSON,
+			DiffSuppressFunc: suppressEquivalentJSON,
+		},
+		"data_stream_lifecycle": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"data_retention": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						ValidateFunc: validateTimeValue,
+					},
+					"enabled": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  true,
+					},
+				},
+			},
+		},
+		"version": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"meta": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateFunc:     validation.StringIsJSON,
+			DiffSuppressFunc: suppressEquivalentJSON,
+		},
+	}
+}
+
+// expandIndexTemplateContent convert the typed fields to template sub section, version and _meta
+func expandIndexTemplateContent(d *schema.ResourceData) (content *IndexTemplateContent, version *int64, metadata map[string]any, err error) {
+	content = &IndexTemplateContent{}
+	isSet := false
+	for key, target := range map[string]*map[string]any{
+		"settings": &content.Settings,
+		"mappings": &content.Mappings,
+		"aliases":  &content.Aliases,
+	} {
+		raw := d.Get(key).(string)
+		if raw == "" {
+			continue
+		}
+		if *target, err = convertRawJsonTopMapString(raw); err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "Error when read %s", key)
+		}
+		isSet = true
+	}
+	if raws := d.Get("data_stream_lifecycle").([]interface{}); len(raws) > 0 {
+		content.Lifecycle = &IndexTemplateLifecycle{}
+		if raws[0] != nil {
+			raw := raws[0].(map[string]interface{})
+			enabled := raw["enabled"].(bool)
+			content.Lifecycle.DataRetention = raw["data_retention"].(string)
+			content.Lifecycle.Enabled = &enabled
+		}
+		isSet = true
+	}
+	if !isSet {
+		content = nil
+	}
+
+	if v, ok := d.GetOk("version"); ok {
+		value := int64(v.(int))
+		version = &value
+	}
+
+	if raw := d.Get("meta").(string); raw != "" {
+		if metadata, err = convertRawJsonTopMapString(raw); err != nil {
+			return nil, nil, nil, errors.Wrap(err, "Error when read meta")
+		}
+	}
+
+	return content, version, metadata, nil
+}
+
+// flattenIndexTemplateContent set the typed fields from template sub section, version and _meta
+func flattenIndexTemplateContent(d *schema.ResourceData, content *IndexTemplateContent, version *int64, metadata map[string]any) (err error) {
+	if content == nil {
+		content = &IndexTemplateContent{}
+	}
+
+	for key, value := range map[string]map[string]any{
+		"settings": content.Settings,
+		"mappings": content.Mappings,
+		"aliases":  content.Aliases,
+		"meta":     metadata,
+	} {
+		flatten, err := convertInterfaceToJsonString(value)
+		if err != nil {
+			return err
+		}
+		if err = d.Set(key, flatten); err != nil {
+			return err
+		}
+	}
+
+	lifecycle := make([]interface{}, 0, 1)
+	if content.Lifecycle != nil {
+		lifecycle = append(lifecycle, map[string]interface{}{
+			"data_retention": content.Lifecycle.DataRetention,
+			"enabled":        content.Lifecycle.Enabled == nil || *content.Lifecycle.Enabled,
+		})
+	}
+	if err = d.Set("data_stream_lifecycle", lifecycle); err != nil {
+		return err
+	}
+
+	if version != nil {
+		err = d.Set("version", int(*version))
+	} else {
+		err = d.Set("version", nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
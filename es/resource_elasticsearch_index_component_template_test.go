@@ -30,6 +30,8 @@ func TestAccElasticsearchIndexComponentTemplate(t *testing.T) {
 				Config: testElasticsearchIndexComponentTemplateUpdate,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckElasticsearchIndexComponentTemplateExists("elasticsearch_index_component_template.test"),
+					resource.TestCheckResourceAttr("elasticsearch_index_component_template.test", "version", "2"),
+					resource.TestCheckResourceAttr("elasticsearch_index_component_template.test", "template", ""),
 				),
 			},
 			{
@@ -124,26 +126,24 @@ EOF
 
 var testElasticsearchIndexComponentTemplateUpdate = `
 resource "elasticsearch_index_component_template" "test" {
-  name 		= "terraform-test"
-  template 	= <<EOF
+  name     = "terraform-test"
+  version  = 2
+  meta     = jsonencode({ owner = "terraform" })
+  settings = jsonencode({
+    "index.refresh_interval" = "3s"
+  })
+  mappings = <<EOF
 {
-	"template": {
-		"settings": {
-			"index.refresh_interval": "3s"
+	"_source": {
+		"enabled": false
+	},
+	"properties": {
+		"host_name": {
+			"type": "keyword"
 		},
-		"mappings": {
-			"_source": {
-				"enabled": false
-			},
-			"properties": {
-				"host_name": {
-					"type": "keyword"
-				},
-				"created_at": {
-					"type": "date",
-					"format": "EEE MMM dd HH:mm:ss Z yyyy"
-				}
-			}
+		"created_at": {
+			"type": "date",
+			"format": "EEE MMM dd HH:mm:ss Z yyyy"
 		}
 	}
 }
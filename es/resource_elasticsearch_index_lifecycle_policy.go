@@ -7,15 +7,113 @@
 package es
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
 	eshandler "github.com/disaster37/es-handler/v8"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	olivere "github.com/olivere/elastic/v7"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
+// IndexLifecyclePolicyPhase is the phase sub section of the policy
+type IndexLifecyclePolicyPhase struct {
+	MinAge  string                      `json:"min_age,omitempty"`
+	Actions IndexLifecyclePolicyActions `json:"actions"`
+}
+
+// IndexLifecyclePolicyActions is the list of actions of a phase
+type IndexLifecyclePolicyActions struct {
+	Rollover           *IndexLifecyclePolicyRollover           `json:"rollover,omitempty"`
+	Shrink             *IndexLifecyclePolicyShrink             `json:"shrink,omitempty"`
+	Forcemerge         *IndexLifecyclePolicyForcemerge         `json:"forcemerge,omitempty"`
+	Allocate           *IndexLifecyclePolicyAllocate           `json:"allocate,omitempty"`
+	SearchableSnapshot *IndexLifecyclePolicySearchableSnapshot `json:"searchable_snapshot,omitempty"`
+	Downsample         *IndexLifecyclePolicyDownsample         `json:"downsample,omitempty"`
+	SetPriority        *IndexLifecyclePolicySetPriority        `json:"set_priority,omitempty"`
+	Migrate            *IndexLifecyclePolicyMigrate            `json:"migrate,omitempty"`
+	Readonly           *struct{}                               `json:"readonly,omitempty"`
+	Unfollow           *struct{}                               `json:"unfollow,omitempty"`
+	WaitForSnapshot    *IndexLifecyclePolicyWaitForSnapshot    `json:"wait_for_snapshot,omitempty"`
+	Delete             *IndexLifecyclePolicyDelete             `json:"delete,omitempty"`
+}
+
+type IndexLifecyclePolicyRollover struct {
+	MaxAge              string `json:"max_age,omitempty"`
+	MaxDocs             int64  `json:"max_docs,omitempty"`
+	MaxSize             string `json:"max_size,omitempty"`
+	MaxPrimaryShardSize string `json:"max_primary_shard_size,omitempty"`
+	MaxPrimaryShardDocs int64  `json:"max_primary_shard_docs,omitempty"`
+	MinAge              string `json:"min_age,omitempty"`
+	MinDocs             int64  `json:"min_docs,omitempty"`
+	MinSize             string `json:"min_size,omitempty"`
+	MinPrimaryShardSize string `json:"min_primary_shard_size,omitempty"`
+	MinPrimaryShardDocs int64  `json:"min_primary_shard_docs,omitempty"`
+}
+
+type IndexLifecyclePolicyShrink struct {
+	NumberOfShards        int64  `json:"number_of_shards,omitempty"`
+	MaxPrimaryShardSize   string `json:"max_primary_shard_size,omitempty"`
+	AllowWriteAfterShrink bool   `json:"allow_write_after_shrink,omitempty"`
+}
+
+type IndexLifecyclePolicyForcemerge struct {
+	MaxNumSegments int64  `json:"max_num_segments"`
+	IndexCodec     string `json:"index_codec,omitempty"`
+}
+
+type IndexLifecyclePolicyAllocate struct {
+	NumberOfReplicas   *int64            `json:"number_of_replicas,omitempty"`
+	TotalShardsPerNode *int64            `json:"total_shards_per_node,omitempty"`
+	Include            map[string]string `json:"include,omitempty"`
+	Exclude            map[string]string `json:"exclude,omitempty"`
+	Require            map[string]string `json:"require,omitempty"`
+}
+
+type IndexLifecyclePolicySearchableSnapshot struct {
+	SnapshotRepository string `json:"snapshot_repository"`
+	ForceMergeIndex    *bool  `json:"force_merge_index,omitempty"`
+}
+
+type IndexLifecyclePolicyDownsample struct {
+	FixedInterval string `json:"fixed_interval"`
+	WaitTimeout   string `json:"wait_timeout,omitempty"`
+}
+
+type IndexLifecyclePolicySetPriority struct {
+	Priority int64 `json:"priority"`
+}
+
+type IndexLifecyclePolicyMigrate struct {
+	Enabled bool `json:"enabled"`
+}
+
+type IndexLifecyclePolicyWaitForSnapshot struct {
+	Policy string `json:"policy"`
+}
+
+type IndexLifecyclePolicyDelete struct {
+	DeleteSearchableSnapshot *bool `json:"delete_searchable_snapshot,omitempty"`
+}
+
+// indexLifecyclePolicyPhases is the list of phases, in the order they are run
+var indexLifecyclePolicyPhases = []string{"hot", "warm", "cold", "frozen", "delete"}
+
+// indexLifecyclePolicyPhaseActions is the list of actions allowed on each phase
+var indexLifecyclePolicyPhaseActions = map[string][]string{
+	"hot":    {"set_priority", "unfollow", "rollover", "readonly", "downsample", "shrink", "forcemerge", "searchable_snapshot"},
+	"warm":   {"set_priority", "unfollow", "readonly", "downsample", "allocate", "migrate", "shrink", "forcemerge"},
+	"cold":   {"set_priority", "unfollow", "readonly", "downsample", "searchable_snapshot", "allocate", "migrate"},
+	"frozen": {"unfollow", "searchable_snapshot"},
+	"delete": {"wait_for_snapshot", "delete"},
+}
+
+// indexLifecyclePolicyHotRolloverActions is the list of actions that need rollover on hot phase
+var indexLifecyclePolicyHotRolloverActions = []string{"readonly", "downsample", "shrink", "forcemerge", "searchable_snapshot"}
+
 // resourceElasticsearchIndexLifecyclePolicy handle the index lifecycle policy API call
 func resourceElasticsearchIndexLifecyclePolicy() *schema.Resource {
 	return &schema.Resource{
@@ -28,6 +126,8 @@ func resourceElasticsearchIndexLifecyclePolicy() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		CustomizeDiff: resourceElasticsearchIndexLifecyclePolicyCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -35,8 +135,10 @@ func resourceElasticsearchIndexLifecyclePolicy() *schema.Resource {
 				Required: true,
 			},
 			"policy": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: indexLifecyclePolicyPhases,
+				AtLeastOneOf:  append([]string{"policy"}, indexLifecyclePolicyPhases...),
 				DiffSuppressFunc: func(k, oldValue, newValue string, d *schema.ResourceData) bool {
 					var err error
 					if oldValue == "" {
@@ -66,6 +168,36 @@ func resourceElasticsearchIndexLifecyclePolicy() *schema.Resource {
 					return diff == ""
 				},
 			},
+			"hot": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem:     indexLifecyclePolicyPhaseSchema("hot"),
+			},
+			"warm": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem:     indexLifecyclePolicyPhaseSchema("warm"),
+			},
+			"cold": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem:     indexLifecyclePolicyPhaseSchema("cold"),
+			},
+			"frozen": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem:     indexLifecyclePolicyPhaseSchema("frozen"),
+			},
+			"delete": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem:     indexLifecyclePolicyPhaseSchema("delete"),
+			},
 		},
 	}
 }
@@ -89,6 +221,49 @@ func resourceElasticsearchIndexLifecyclePolicyUpdate(d *schema.ResourceData, met
 	return resourceElasticsearchIndexLifecyclePolicyRead(d, meta)
 }
 
+// resourceElasticsearchIndexLifecyclePolicyCustomizeDiff check the phases at plan time
+// min_age must increase from phase to phase and some hot actions need rollover
+// Unknown values are skipped
+func resourceElasticsearchIndexLifecyclePolicyCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) (err error) {
+	var previousPhase, previousMinAge string
+	for _, phase := range indexLifecyclePolicyPhases {
+		raws := d.Get(phase).([]interface{})
+		if len(raws) == 0 || raws[0] == nil {
+			continue
+		}
+		raw := raws[0].(map[string]interface{})
+
+		minAge, err := parseTimeValue(raw["min_age"].(string))
+		if err != nil {
+			continue
+		}
+		if previousPhase != "" {
+			previous, _ := parseTimeValue(previousMinAge)
+			if minAge < previous {
+				return errors.Errorf("The %s phase (min_age: %s) must not run before the %s phase (min_age: %s)", phase, raw["min_age"].(string), previousPhase, previousMinAge)
+			}
+		}
+		previousPhase = phase
+		previousMinAge = raw["min_age"].(string)
+	}
+
+	raws := d.Get("hot").([]interface{})
+	if len(raws) == 0 || raws[0] == nil {
+		return nil
+	}
+	raw := raws[0].(map[string]interface{})
+	if len(raw["rollover"].([]interface{})) > 0 {
+		return nil
+	}
+	for _, action := range indexLifecyclePolicyHotRolloverActions {
+		if isIndexLifecyclePolicyActionSet(raw[action]) {
+			return errors.Errorf("The %s action on hot phase need the rollover action", action)
+		}
+	}
+
+	return nil
+}
+
 // resourceElasticsearchIndexLifecyclePolicyRead read index lifecycle policy
 func resourceElasticsearchIndexLifecyclePolicyRead(d *schema.ResourceData, meta interface{}) (err error) {
 	id := d.Id()
@@ -109,13 +284,28 @@ func resourceElasticsearchIndexLifecyclePolicyRead(d *schema.ResourceData, meta
 		return err
 	}
 
-	flattenPolicy, err := convertInterfaceToJsonString(policy)
+	// Keep raw policy if it's what is used
+	if _, usePolicy := d.GetOk("policy"); usePolicy {
+		flattenPolicy, err := convertInterfaceToJsonString(policy)
+		if err != nil {
+			return err
+		}
+		if err = d.Set("policy", flattenPolicy); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	phases, err := getIndexLifecyclePolicyPhases(policy)
 	if err != nil {
 		return err
 	}
-	if err = d.Set("policy", flattenPolicy); err != nil {
-		return err
+	for _, phaseName := range indexLifecyclePolicyPhases {
+		if err = d.Set(phaseName, flattenIndexLifecyclePolicyPhase(phaseName, phases[phaseName])); err != nil {
+			return err
+		}
 	}
+
 	return nil
 }
 
@@ -138,8 +328,20 @@ func createIndexLifecyclePolicy(d *schema.ResourceData, meta interface{}) (err e
 	policy := d.Get("policy").(string)
 
 	data := &olivere.XPackIlmGetLifecycleResponse{}
-	if err = json.Unmarshal([]byte(policy), data); err != nil {
-		return err
+	if policy != "" {
+		if err = json.Unmarshal([]byte(policy), data); err != nil {
+			return err
+		}
+	} else {
+		phases := map[string]*IndexLifecyclePolicyPhase{}
+		for _, phaseName := range indexLifecyclePolicyPhases {
+			if raws := d.Get(phaseName).([]interface{}); len(raws) > 0 && raws[0] != nil {
+				phases[phaseName] = expandIndexLifecyclePolicyPhase(phaseName, raws[0].(map[string]interface{}))
+			}
+		}
+		data.Policy = map[string]interface{}{
+			"phases": phases,
+		}
 	}
 
 	client := meta.(eshandler.ElasticsearchHandler)
@@ -149,3 +351,433 @@ func createIndexLifecyclePolicy(d *schema.ResourceData, meta interface{}) (err e
 
 	return nil
 }
+
+// getIndexLifecyclePolicyPhases extract the typed phases from the policy
+func getIndexLifecyclePolicyPhases(policy *olivere.XPackIlmGetLifecycleResponse) (phases map[string]*IndexLifecyclePolicyPhase, err error) {
+	phases = map[string]*IndexLifecyclePolicyPhase{}
+	if policy.Policy == nil || policy.Policy["phases"] == nil {
+		return phases, nil
+	}
+
+	b, err := json.Marshal(policy.Policy["phases"])
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &phases); err != nil {
+		return nil, err
+	}
+
+	return phases, nil
+}
+
+// isIndexLifecyclePolicyActionSet return true if the action is set on phase block
+func isIndexLifecyclePolicyActionSet(raw interface{}) bool {
+	switch v := raw.(type) {
+	case bool:
+		return v
+	case []interface{}:
+		return len(v) > 0
+	default:
+		return false
+	}
+}
+
+// indexLifecyclePolicyPhaseSchema return the phase schema with only the allowed actions
+func indexLifecyclePolicyPhaseSchema(phase string) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"min_age": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "0ms",
+			ValidateFunc: validateTimeValue,
+		},
+	}
+
+	for _, action := range indexLifecyclePolicyPhaseActions[phase] {
+		s[action] = indexLifecyclePolicyActionSchema(phase, action)
+	}
+
+	return &schema.Resource{
+		Schema: s,
+	}
+}
+
+// indexLifecyclePolicyActionSchema return the action schema
+func indexLifecyclePolicyActionSchema(phase string, action string) *schema.Schema {
+	var s map[string]*schema.Schema
+
+	switch action {
+	case "readonly", "unfollow":
+		return &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		}
+	case "rollover":
+		s = map[string]*schema.Schema{}
+		for _, key := range []string{"max_age", "min_age"} {
+			s[key] = &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateTimeValue,
+			}
+		}
+		for _, key := range []string{"max_size", "max_primary_shard_size", "min_size", "min_primary_shard_size"} {
+			s[key] = &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateByteSize,
+			}
+		}
+		for _, key := range []string{"max_docs", "max_primary_shard_docs", "min_docs", "min_primary_shard_docs"} {
+			s[key] = &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			}
+		}
+	case "shrink":
+		exactlyOneOf := []string{
+			fmt.Sprintf("%s.0.shrink.0.number_of_shards", phase),
+			fmt.Sprintf("%s.0.shrink.0.max_primary_shard_size", phase),
+		}
+		s = map[string]*schema.Schema{
+			"number_of_shards": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				ExactlyOneOf: exactlyOneOf,
+			},
+			"max_primary_shard_size": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateByteSize,
+				ExactlyOneOf: exactlyOneOf,
+			},
+			"allow_write_after_shrink": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		}
+	case "forcemerge":
+		s = map[string]*schema.Schema{
+			"max_num_segments": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"index_codec": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"best_compression"}, false),
+			},
+		}
+	case "allocate":
+		s = map[string]*schema.Schema{
+			"number_of_replicas": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      -1,
+				ValidateFunc: validation.IntAtLeast(-1),
+			},
+			"total_shards_per_node": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(-1),
+			},
+		}
+		for _, key := range []string{"include", "exclude", "require"} {
+			s[key] = &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			}
+		}
+	case "searchable_snapshot":
+		s = map[string]*schema.Schema{
+			"snapshot_repository": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"force_merge_index": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		}
+	case "downsample":
+		s = map[string]*schema.Schema{
+			"fixed_interval": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateTimeValue,
+			},
+			"wait_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateTimeValue,
+			},
+		}
+	case "set_priority":
+		s = map[string]*schema.Schema{
+			"priority": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+		}
+	case "migrate":
+		s = map[string]*schema.Schema{
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		}
+	case "wait_for_snapshot":
+		s = map[string]*schema.Schema{
+			"policy": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		}
+	case "delete":
+		s = map[string]*schema.Schema{
+			"delete_searchable_snapshot": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		}
+	}
+
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: s,
+		},
+	}
+}
+
+// expandIndexLifecyclePolicyPhase convert phase block to API object
+func expandIndexLifecyclePolicyPhase(phase string, raw map[string]interface{}) *IndexLifecyclePolicyPhase {
+	p := &IndexLifecyclePolicyPhase{
+		MinAge: raw["min_age"].(string),
+	}
+
+	for _, action := range indexLifecyclePolicyPhaseActions[phase] {
+		if !isIndexLifecyclePolicyActionSet(raw[action]) {
+			continue
+		}
+
+		// Empty block is nil
+		params := map[string]interface{}{}
+		if raws, ok := raw[action].([]interface{}); ok && raws[0] != nil {
+			params = raws[0].(map[string]interface{})
+		}
+
+		switch action {
+		case "readonly":
+			p.Actions.Readonly = &struct{}{}
+		case "unfollow":
+			p.Actions.Unfollow = &struct{}{}
+		case "rollover":
+			p.Actions.Rollover = &IndexLifecyclePolicyRollover{
+				MaxAge:              getString(params, "max_age"),
+				MaxDocs:             int64(getInt(params, "max_docs")),
+				MaxSize:             getString(params, "max_size"),
+				MaxPrimaryShardSize: getString(params, "max_primary_shard_size"),
+				MaxPrimaryShardDocs: int64(getInt(params, "max_primary_shard_docs")),
+				MinAge:              getString(params, "min_age"),
+				MinDocs:             int64(getInt(params, "min_docs")),
+				MinSize:             getString(params, "min_size"),
+				MinPrimaryShardSize: getString(params, "min_primary_shard_size"),
+				MinPrimaryShardDocs: int64(getInt(params, "min_primary_shard_docs")),
+			}
+		case "shrink":
+			p.Actions.Shrink = &IndexLifecyclePolicyShrink{
+				NumberOfShards:        int64(getInt(params, "number_of_shards")),
+				MaxPrimaryShardSize:   getString(params, "max_primary_shard_size"),
+				AllowWriteAfterShrink: getBool(params, "allow_write_after_shrink", false),
+			}
+		case "forcemerge":
+			p.Actions.Forcemerge = &IndexLifecyclePolicyForcemerge{
+				MaxNumSegments: int64(getInt(params, "max_num_segments")),
+				IndexCodec:     getString(params, "index_codec"),
+			}
+		case "allocate":
+			allocate := &IndexLifecyclePolicyAllocate{
+				Include: getMapString(params, "include"),
+				Exclude: getMapString(params, "exclude"),
+				Require: getMapString(params, "require"),
+			}
+			// -1 is used to unset number_of_replicas, because 0 is a valid value
+			if numberOfReplicas, ok := params["number_of_replicas"].(int); ok && numberOfReplicas >= 0 {
+				v := int64(numberOfReplicas)
+				allocate.NumberOfReplicas = &v
+			}
+			if totalShardsPerNode := getInt(params, "total_shards_per_node"); totalShardsPerNode != 0 {
+				v := int64(totalShardsPerNode)
+				allocate.TotalShardsPerNode = &v
+			}
+			p.Actions.Allocate = allocate
+		case "searchable_snapshot":
+			forceMergeIndex := getBool(params, "force_merge_index", true)
+			p.Actions.SearchableSnapshot = &IndexLifecyclePolicySearchableSnapshot{
+				SnapshotRepository: getString(params, "snapshot_repository"),
+				ForceMergeIndex:    &forceMergeIndex,
+			}
+		case "downsample":
+			p.Actions.Downsample = &IndexLifecyclePolicyDownsample{
+				FixedInterval: getString(params, "fixed_interval"),
+				WaitTimeout:   getString(params, "wait_timeout"),
+			}
+		case "set_priority":
+			p.Actions.SetPriority = &IndexLifecyclePolicySetPriority{
+				Priority: int64(getInt(params, "priority")),
+			}
+		case "migrate":
+			p.Actions.Migrate = &IndexLifecyclePolicyMigrate{
+				Enabled: getBool(params, "enabled", true),
+			}
+		case "wait_for_snapshot":
+			p.Actions.WaitForSnapshot = &IndexLifecyclePolicyWaitForSnapshot{
+				Policy: getString(params, "policy"),
+			}
+		case "delete":
+			deleteSearchableSnapshot := getBool(params, "delete_searchable_snapshot", true)
+			p.Actions.Delete = &IndexLifecyclePolicyDelete{
+				DeleteSearchableSnapshot: &deleteSearchableSnapshot,
+			}
+		}
+	}
+
+	return p
+}
+
+// flattenIndexLifecyclePolicyPhase convert API object to phase block
+func flattenIndexLifecyclePolicyPhase(phase string, p *IndexLifecyclePolicyPhase) []interface{} {
+	if p == nil {
+		return []interface{}{}
+	}
+
+	minAge := p.MinAge
+	if minAge == "" {
+		minAge = "0ms"
+	}
+	raw := map[string]interface{}{
+		"min_age": minAge,
+	}
+	actions := p.Actions
+
+	for _, action := range indexLifecyclePolicyPhaseActions[phase] {
+		var params map[string]interface{}
+
+		switch action {
+		case "readonly":
+			raw[action] = actions.Readonly != nil
+			continue
+		case "unfollow":
+			raw[action] = actions.Unfollow != nil
+			continue
+		case "rollover":
+			if a := actions.Rollover; a != nil {
+				params = map[string]interface{}{
+					"max_age":                a.MaxAge,
+					"max_docs":               a.MaxDocs,
+					"max_size":               a.MaxSize,
+					"max_primary_shard_size": a.MaxPrimaryShardSize,
+					"max_primary_shard_docs": a.MaxPrimaryShardDocs,
+					"min_age":                a.MinAge,
+					"min_docs":               a.MinDocs,
+					"min_size":               a.MinSize,
+					"min_primary_shard_size": a.MinPrimaryShardSize,
+					"min_primary_shard_docs": a.MinPrimaryShardDocs,
+				}
+			}
+		case "shrink":
+			if a := actions.Shrink; a != nil {
+				params = map[string]interface{}{
+					"number_of_shards":         a.NumberOfShards,
+					"max_primary_shard_size":   a.MaxPrimaryShardSize,
+					"allow_write_after_shrink": a.AllowWriteAfterShrink,
+				}
+			}
+		case "forcemerge":
+			if a := actions.Forcemerge; a != nil {
+				params = map[string]interface{}{
+					"max_num_segments": a.MaxNumSegments,
+					"index_codec":      a.IndexCodec,
+				}
+			}
+		case "allocate":
+			if a := actions.Allocate; a != nil {
+				params = map[string]interface{}{
+					"number_of_replicas":    int64(-1),
+					"total_shards_per_node": int64(0),
+					"include":               a.Include,
+					"exclude":               a.Exclude,
+					"require":               a.Require,
+				}
+				if a.NumberOfReplicas != nil {
+					params["number_of_replicas"] = *a.NumberOfReplicas
+				}
+				if a.TotalShardsPerNode != nil {
+					params["total_shards_per_node"] = *a.TotalShardsPerNode
+				}
+			}
+		case "searchable_snapshot":
+			if a := actions.SearchableSnapshot; a != nil {
+				params = map[string]interface{}{
+					"snapshot_repository": a.SnapshotRepository,
+					"force_merge_index":   a.ForceMergeIndex == nil || *a.ForceMergeIndex,
+				}
+			}
+		case "downsample":
+			if a := actions.Downsample; a != nil {
+				params = map[string]interface{}{
+					"fixed_interval": a.FixedInterval,
+					"wait_timeout":   a.WaitTimeout,
+				}
+			}
+		case "set_priority":
+			if a := actions.SetPriority; a != nil {
+				params = map[string]interface{}{
+					"priority": a.Priority,
+				}
+			}
+		case "migrate":
+			if a := actions.Migrate; a != nil {
+				params = map[string]interface{}{
+					"enabled": a.Enabled,
+				}
+			}
+		case "wait_for_snapshot":
+			if a := actions.WaitForSnapshot; a != nil {
+				params = map[string]interface{}{
+					"policy": a.Policy,
+				}
+			}
+		case "delete":
+			if a := actions.Delete; a != nil {
+				params = map[string]interface{}{
+					"delete_searchable_snapshot": a.DeleteSearchableSnapshot == nil || *a.DeleteSearchableSnapshot,
+				}
+			}
+		}
+
+		if params != nil {
+			raw[action] = []interface{}{params}
+		} else {
+			raw[action] = []interface{}{}
+		}
+	}
+
+	return []interface{}{raw}
+}
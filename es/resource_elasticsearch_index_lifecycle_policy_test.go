@@ -2,6 +2,7 @@ package es
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	eshandler "github.com/disaster37/es-handler/v8"
@@ -19,6 +20,10 @@ func TestAccElasticsearchIndexLifecyclePolicy(t *testing.T) {
 		Providers:    testAccProviders,
 		CheckDestroy: testCheckElasticsearchIndexLifecyclePolicyDestroy,
 		Steps: []resource.TestStep{
+			{
+				Config:      testElasticsearchIndexLifecyclePolicyInvalidMinAge,
+				ExpectError: regexp.MustCompile("must not run before the warm phase"),
+			},
 			{
 				Config: testElasticsearchIndexLifecyclePolicy,
 				Check: resource.ComposeTestCheckFunc(
@@ -31,6 +36,14 @@ func TestAccElasticsearchIndexLifecyclePolicy(t *testing.T) {
 					testCheckElasticsearchIndexLifecyclePolicyExists("elasticsearch_index_lifecycle_policy.test"),
 				),
 			},
+			{
+				Config: testElasticsearchIndexLifecyclePolicyPhases,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchIndexLifecyclePolicyExists("elasticsearch_index_lifecycle_policy.test"),
+					resource.TestCheckResourceAttr("elasticsearch_index_lifecycle_policy.test", "warm.0.allocate.0.number_of_replicas", "0"),
+					resource.TestCheckResourceAttr("elasticsearch_index_lifecycle_policy.test", "hot.0.rollover.0.max_age", "1d"),
+				),
+			},
 			{
 				ResourceName:      "elasticsearch_index_lifecycle_policy.test",
 				ImportState:       true,
@@ -151,3 +164,51 @@ resource "elasticsearch_index_lifecycle_policy" "test" {
 EOF
 }
 `
+
+var testElasticsearchIndexLifecyclePolicyPhases = `
+resource "elasticsearch_index_lifecycle_policy" "test" {
+  name = "terraform-test"
+  hot {
+    rollover {
+      max_age                = "1d"
+      max_primary_shard_size = "50gb"
+    }
+    forcemerge {
+      max_num_segments = 1
+    }
+  }
+  warm {
+    min_age  = "10d"
+    readonly = true
+    set_priority {
+      priority = 0
+    }
+    allocate {
+      number_of_replicas = 0
+      require = {
+        data = "warm"
+      }
+    }
+  }
+  delete {
+    min_age = "31d"
+    delete {
+      delete_searchable_snapshot = true
+    }
+  }
+}
+`
+
+var testElasticsearchIndexLifecyclePolicyInvalidMinAge = `
+resource "elasticsearch_index_lifecycle_policy" "test" {
+  name = "terraform-test"
+  warm {
+    min_age  = "10d"
+    readonly = true
+  }
+  delete {
+    min_age = "1d"
+    delete {}
+  }
+}
+`
@@ -6,18 +6,52 @@
 package es
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"strings"
 
 	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	olivere "github.com/olivere/elastic/v7"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
+// IndexTemplate is the composable index template
+type IndexTemplate struct {
+	IndexPatterns []string                 `json:"index_patterns,omitempty"`
+	ComposedOf    []string                 `json:"composed_of,omitempty"`
+	Priority      *int64                   `json:"priority,omitempty"`
+	Version       *int64                   `json:"version,omitempty"`
+	DataStream    *IndexTemplateDataStream `json:"data_stream,omitempty"`
+	Meta          map[string]any           `json:"_meta,omitempty"`
+	Template      *IndexTemplateContent    `json:"template,omitempty"`
+}
+
+// IndexTemplateDataStream is the data stream sub section
+type IndexTemplateDataStream struct {
+	Hidden             bool `json:"hidden"`
+	AllowCustomRouting bool `json:"allow_custom_routing"`
+}
+
+// IndexTemplateGetResponse is the get index template API response
+type IndexTemplateGetResponse struct {
+	IndexTemplates []struct {
+		Name          string        `json:"name"`
+		IndexTemplate IndexTemplate `json:"index_template"`
+	} `json:"index_templates"`
+}
+
+// indexTemplateFields is the list of typed fields of index template
+var indexTemplateFields = append([]string{"index_patterns", "composed_of", "priority", "data_stream"}, indexTemplateContentFields...)
+
 // resourceElasticsearchIndexTemplate handle the index template API call
 func resourceElasticsearchIndexTemplate() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceElasticsearchIndexTemplateCreate,
 		Update: resourceElasticsearchIndexTemplateUpdate,
 		Read:   resourceElasticsearchIndexTemplateRead,
@@ -27,6 +61,11 @@ func resourceElasticsearchIndexTemplate() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		CustomizeDiff: customdiff.All(
+			resourceElasticsearchIndexTemplateCustomizeDiffComposedOf,
+			resourceElasticsearchIndexTemplateCustomizeDiffPriority,
+		),
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -34,8 +73,10 @@ func resourceElasticsearchIndexTemplate() *schema.Resource {
 				Required: true,
 			},
 			"template": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: indexTemplateFields,
+				AtLeastOneOf:  []string{"template", "index_patterns"},
 				DiffSuppressFunc: func(k, oldValue, newValue string, d *schema.ResourceData) bool {
 					var err error
 
@@ -66,8 +107,52 @@ func resourceElasticsearchIndexTemplate() *schema.Resource {
 					return diff == ""
 				},
 			},
+			"index_patterns": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"composed_of": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"priority": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"data_stream": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"hidden": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"allow_custom_routing": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+					},
+				},
+			},
 		},
 	}
+
+	for key, value := range indexTemplateContentSchema() {
+		r.Schema[key] = value
+	}
+
+	return r
 }
 
 // resourceElasticsearchIndexTemplateCreate create index template
@@ -90,10 +175,77 @@ func resourceElasticsearchIndexTemplateUpdate(d *schema.ResourceData, meta inter
 	return resourceElasticsearchIndexTemplateRead(d, meta)
 }
 
+// resourceElasticsearchIndexTemplateCustomizeDiffComposedOf check that component templates exist
+// Unknown component templates are skipped, like the ones created on the same apply
+func resourceElasticsearchIndexTemplateCustomizeDiffComposedOf(ctx context.Context, d *schema.ResourceDiff, meta interface{}) (err error) {
+	if !d.HasChanges("composed_of", "template") {
+		return nil
+	}
+
+	composedOf, err := getIndexTemplateComposedOfFromDiff(d)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range composedOf {
+		ct, err := getIndexComponentTemplate(name, meta)
+		if err != nil {
+			return err
+		}
+		if ct == nil {
+			return errors.Errorf("Component template %s not found. If it's created on the same apply, reference it with the id attribute of elasticsearch_index_component_template", name)
+		}
+	}
+
+	return nil
+}
+
+// resourceElasticsearchIndexTemplateCustomizeDiffPriority check that no other index template has the same priority with overlapping index patterns
+func resourceElasticsearchIndexTemplateCustomizeDiffPriority(ctx context.Context, d *schema.ResourceDiff, meta interface{}) (err error) {
+	if !d.HasChanges("index_patterns", "priority", "template") {
+		return nil
+	}
+	if !d.NewValueKnown("index_patterns") || !d.NewValueKnown("priority") || !d.NewValueKnown("template") {
+		return nil
+	}
+
+	name := d.Get("name").(string)
+	template := &IndexTemplate{}
+	if raw := d.Get("template").(string); raw != "" {
+		if err = json.Unmarshal([]byte(raw), template); err != nil {
+			return err
+		}
+	} else {
+		template.IndexPatterns = convertArrayInterfaceToArrayString(d.Get("index_patterns").([]interface{}))
+		priority := int64(d.Get("priority").(int))
+		template.Priority = &priority
+	}
+
+	templates, err := listIndexTemplates(meta)
+	if err != nil {
+		return err
+	}
+	for _, other := range templates.IndexTemplates {
+		if other.Name == name || getIndexTemplatePriority(&other.IndexTemplate) != getIndexTemplatePriority(template) {
+			continue
+		}
+		if pattern, otherPattern, isOverlap := getIndexPatternsOverlap(template.IndexPatterns, other.IndexTemplate.IndexPatterns); isOverlap {
+			return errors.Errorf("Index template %s has the same priority %d than index template %s, and the index pattern %s overlap %s", name, getIndexTemplatePriority(template), other.Name, pattern, otherPattern)
+		}
+	}
+
+	return nil
+}
+
 // resourceElasticsearchIndexTemplateRead read index template
 func resourceElasticsearchIndexTemplateRead(d *schema.ResourceData, meta interface{}) (err error) {
 	id := d.Id()
 
+	// Use typed fields if raw template is not used
+	if _, useTemplate := d.GetOk("template"); !useTemplate {
+		return readIndexTemplate(d, meta)
+	}
+
 	client := meta.(eshandler.ElasticsearchHandler)
 	it, err := client.IndexTemplateGet(id)
 	if err != nil {
@@ -144,6 +296,10 @@ func createIndexTemplate(d *schema.ResourceData, meta interface{}) (err error) {
 
 	client := meta.(eshandler.ElasticsearchHandler)
 
+	if template == "" {
+		return putIndexTemplate(d, meta)
+	}
+
 	data := &olivere.IndicesGetIndexTemplate{}
 	if err = json.Unmarshal([]byte(template), data); err != nil {
 		return err
@@ -154,3 +310,244 @@ func createIndexTemplate(d *schema.ResourceData, meta interface{}) (err error) {
 
 	return nil
 }
+
+// readIndexTemplate read the index template on typed fields
+func readIndexTemplate(d *schema.ResourceData, meta interface{}) (err error) {
+	id := d.Id()
+
+	it, err := getIndexTemplate(id, meta)
+	if err != nil {
+		return err
+	}
+	if it == nil {
+		fmt.Printf("[WARN] Index template %s not found - removing from state", id)
+		log.Warnf("Index template %s not found - removing from state", id)
+		d.SetId("")
+		return nil
+	}
+
+	if err = d.Set("name", id); err != nil {
+		return err
+	}
+	if err = d.Set("index_patterns", it.IndexPatterns); err != nil {
+		return err
+	}
+	if err = d.Set("composed_of", it.ComposedOf); err != nil {
+		return err
+	}
+	if it.Priority != nil {
+		err = d.Set("priority", int(*it.Priority))
+	} else {
+		err = d.Set("priority", nil)
+	}
+	if err != nil {
+		return err
+	}
+	dataStream := make([]interface{}, 0, 1)
+	if it.DataStream != nil {
+		dataStream = append(dataStream, map[string]interface{}{
+			"hidden":               it.DataStream.Hidden,
+			"allow_custom_routing": it.DataStream.AllowCustomRouting,
+		})
+	}
+	if err = d.Set("data_stream", dataStream); err != nil {
+		return err
+	}
+	if err = flattenIndexTemplateContent(d, it.Template, it.Version, it.Meta); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// putIndexTemplate create or update index template from typed fields
+func putIndexTemplate(d *schema.ResourceData, meta interface{}) (err error) {
+	name := d.Get("name").(string)
+
+	data := &IndexTemplate{
+		IndexPatterns: convertArrayInterfaceToArrayString(d.Get("index_patterns").([]interface{})),
+		ComposedOf:    convertArrayInterfaceToArrayString(d.Get("composed_of").([]interface{})),
+	}
+	if v, ok := d.GetOk("priority"); ok {
+		priority := int64(v.(int))
+		data.Priority = &priority
+	}
+	if raws := d.Get("data_stream").([]interface{}); len(raws) > 0 {
+		data.DataStream = &IndexTemplateDataStream{}
+		if raws[0] != nil {
+			raw := raws[0].(map[string]interface{})
+			data.DataStream.Hidden = raw["hidden"].(bool)
+			data.DataStream.AllowCustomRouting = raw["allow_custom_routing"].(bool)
+		}
+	}
+	if data.Template, data.Version, data.Meta, err = expandIndexTemplateContent(d); err != nil {
+		return err
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Indices.PutIndexTemplate(
+		name,
+		bytes.NewReader(b),
+		client.API.Indices.PutIndexTemplate.WithContext(context.Background()),
+		client.API.Indices.PutIndexTemplate.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when add index template %s: %s", name, res.String())
+	}
+
+	log.Infof("Add index template %s successfully", name)
+
+	return nil
+}
+
+// getIndexTemplate return the index template or nil if not exist
+func getIndexTemplate(name string, meta interface{}) (it *IndexTemplate, err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Indices.GetIndexTemplate(
+		client.API.Indices.GetIndexTemplate.WithName(name),
+		client.API.Indices.GetIndexTemplate.WithContext(context.Background()),
+		client.API.Indices.GetIndexTemplate.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get index template %s: %s", name, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	templates := &IndexTemplateGetResponse{}
+	if err = json.Unmarshal(b, templates); err != nil {
+		return nil, err
+	}
+
+	for _, template := range templates.IndexTemplates {
+		if template.Name == name {
+			return &template.IndexTemplate, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// listIndexTemplates return all index templates
+func listIndexTemplates(meta interface{}) (templates *IndexTemplateGetResponse, err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Indices.GetIndexTemplate(
+		client.API.Indices.GetIndexTemplate.WithContext(context.Background()),
+		client.API.Indices.GetIndexTemplate.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	templates = &IndexTemplateGetResponse{}
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return templates, nil
+		}
+		return nil, errors.Errorf("Error when get index templates: %s", res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, templates); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// getIndexTemplateComposedOfFromDiff return the known component templates from raw template or typed fields
+func getIndexTemplateComposedOfFromDiff(d *schema.ResourceDiff) (composedOf []string, err error) {
+	composedOf = make([]string, 0)
+
+	if raw := d.Get("template").(string); raw != "" {
+		if !d.NewValueKnown("template") {
+			return composedOf, nil
+		}
+		template := &IndexTemplate{}
+		if err = json.Unmarshal([]byte(raw), template); err != nil {
+			return nil, err
+		}
+		return template.ComposedOf, nil
+	}
+
+	for i, raw := range d.Get("composed_of").([]interface{}) {
+		if !d.NewValueKnown(fmt.Sprintf("composed_of.%d", i)) {
+			continue
+		}
+		composedOf = append(composedOf, raw.(string))
+	}
+
+	return composedOf, nil
+}
+
+// getIndexTemplatePriority return the priority of index template, it's 0 when not set
+func getIndexTemplatePriority(template *IndexTemplate) int64 {
+	if template.Priority == nil {
+		return 0
+	}
+	return *template.Priority
+}
+
+// getIndexPatternsOverlap return the first index patterns that overlap
+func getIndexPatternsOverlap(patterns []string, otherPatterns []string) (pattern string, otherPattern string, isOverlap bool) {
+	for _, pattern = range patterns {
+		for _, otherPattern = range otherPatterns {
+			if indexPatternsOverlap(pattern, otherPattern) {
+				return pattern, otherPattern, true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+// indexPatternsOverlap check if an index name can match the two index patterns, only the wildcard `*` is supported like Elasticsearch
+func indexPatternsOverlap(a string, b string) bool {
+	memo := map[[2]int]bool{}
+	var overlap func(i, j int) bool
+	overlap = func(i, j int) bool {
+		key := [2]int{i, j}
+		if result, ok := memo[key]; ok {
+			return result
+		}
+
+		var result bool
+		switch {
+		case i == len(a):
+			result = strings.Trim(b[j:], "*") == ""
+		case j == len(b):
+			result = strings.Trim(a[i:], "*") == ""
+		case a[i] == '*':
+			result = overlap(i+1, j) || overlap(i, j+1)
+		case b[j] == '*':
+			result = overlap(i, j+1) || overlap(i+1, j)
+		default:
+			result = a[i] == b[j] && overlap(i+1, j+1)
+		}
+
+		memo[key] = result
+		return result
+	}
+
+	return overlap(0, 0)
+}
@@ -2,6 +2,7 @@ package es
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	eshandler "github.com/disaster37/es-handler/v8"
@@ -20,6 +21,21 @@ func TestAccElasticsearchIndexTemplate(t *testing.T) {
 		Providers:    testAccProviders,
 		CheckDestroy: testCheckElasticsearchIndexTemplateDestroy,
 		Steps: []resource.TestStep{
+			{
+				Config:      testElasticsearchIndexTemplateMissingComponent,
+				ExpectError: regexp.MustCompile("Component template terraform-test-missing not found"),
+			},
+			{
+				Config: testElasticsearchIndexTemplateOther,
+			},
+			{
+				Config:      testElasticsearchIndexTemplatePriorityCollision,
+				ExpectError: regexp.MustCompile("same priority 2 than index template terraform-test-index-template-other"),
+			},
+			{
+				// The collision check use the templates on cluster, so the other template must not overlap before it's removed
+				Config: testElasticsearchIndexTemplateOtherPriority,
+			},
 			{
 				Config: testElasticsearchIndexTemplate,
 				Check: resource.ComposeTestCheckFunc(
@@ -30,6 +46,8 @@ func TestAccElasticsearchIndexTemplate(t *testing.T) {
 				Config: testElasticsearchIndexTemplateUpdate,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckElasticsearchIndexTemplateExists("elasticsearch_index_template.test"),
+					resource.TestCheckResourceAttr("elasticsearch_index_template.test", "index_patterns.0", "test-index-template"),
+					resource.TestCheckResourceAttr("elasticsearch_index_template.test", "priority", "2"),
 				),
 			},
 			{
@@ -113,19 +131,51 @@ EOF
 
 var testElasticsearchIndexTemplateUpdate = `
 resource "elasticsearch_index_template" "test" {
-  name 		= "terraform-test-index-template"
-  template 	= <<EOF
+  name           = "terraform-test-index-template"
+  index_patterns = ["test-index-template"]
+  priority       = 2
+  version        = 1
+  meta           = jsonencode({ owner = "terraform" })
+  settings       = <<EOF
 {
-	"index_patterns": ["test-index-template"],
-	"template": {
-		"settings": {
-			"index.refresh_interval": "3s",
-			"index.lifecycle.name": "policy-logstash-backup",
-    		"index.lifecycle.rollover_alias": "logstash-backup-alias"
-		}
-	},
-	"priority": 2
+	"index.refresh_interval": "3s",
+	"index.lifecycle.name": "policy-logstash-backup",
+	"index.lifecycle.rollover_alias": "logstash-backup-alias"
 }
 EOF
+  aliases = jsonencode({ "test-index-template-alias" = {} })
+}
+`
+
+var testElasticsearchIndexTemplateMissingComponent = `
+resource "elasticsearch_index_template" "test" {
+  name           = "terraform-test-index-template"
+  index_patterns = ["test-index-template"]
+  composed_of    = ["terraform-test-missing"]
+  priority       = 2
+}
+`
+
+var testElasticsearchIndexTemplateOther = `
+resource "elasticsearch_index_template" "other" {
+  name           = "terraform-test-index-template-other"
+  index_patterns = ["test-index-*"]
+  priority       = 2
+}
+`
+
+var testElasticsearchIndexTemplateOtherPriority = `
+resource "elasticsearch_index_template" "other" {
+  name           = "terraform-test-index-template-other"
+  index_patterns = ["test-index-*"]
+  priority       = 3
+}
+`
+
+var testElasticsearchIndexTemplatePriorityCollision = testElasticsearchIndexTemplateOther + `
+resource "elasticsearch_index_template" "test" {
+  name           = "terraform-test-index-template"
+  index_patterns = ["test-index-template"]
+  priority       = 2
 }
 `
@@ -6,16 +6,33 @@
 package es
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 
 	eshandler "github.com/disaster37/es-handler/v8"
-	olivere "github.com/olivere/elastic/v7"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
+// IngestPipeline is the ingest pipeline
+// Processors are kept as is to not lose the fields added on new Elasticsearch versions
+type IngestPipeline struct {
+	Description string           `json:"description,omitempty"`
+	Version     *int64           `json:"version,omitempty"`
+	Meta        map[string]any   `json:"_meta,omitempty"`
+	Deprecated  *bool            `json:"deprecated,omitempty"`
+	Processors  []map[string]any `json:"processors,omitempty"`
+	OnFailure   []map[string]any `json:"on_failure,omitempty"`
+}
+
+// IngestPipelineGetResponse is the get ingest pipeline API response
+type IngestPipelineGetResponse map[string]IngestPipeline
+
 // resourceElasticsearchIngestPipeline handle the ingest pipeline API call
 func resourceElasticsearchIngestPipeline() *schema.Resource {
 	return &schema.Resource{
@@ -67,8 +84,7 @@ func resourceElasticsearchIngestPipelineUpdate(d *schema.ResourceData, meta inte
 func resourceElasticsearchIngestPipelineRead(d *schema.ResourceData, meta interface{}) (err error) {
 	id := d.Id()
 
-	client := meta.(eshandler.ElasticsearchHandler)
-	pipeline, err := client.IngestPipelineGet(id)
+	pipeline, err := getIngestPipeline(id, meta)
 	if err != nil {
 		return err
 	}
@@ -114,15 +130,81 @@ func createIngestPipeline(d *schema.ResourceData, meta interface{}) (err error)
 	name := d.Get("name").(string)
 	pipeline := d.Get("pipeline").(string)
 
-	client := meta.(eshandler.ElasticsearchHandler)
-
-	data := &olivere.IngestGetPipeline{}
+	data := &IngestPipeline{}
 	if err = json.Unmarshal([]byte(pipeline), data); err != nil {
 		return err
 	}
-	if err = client.IngestPipelineUpdate(name, data); err != nil {
+	b, err := json.Marshal(data)
+	if err != nil {
 		return err
 	}
 
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Ingest.PutPipeline(
+		name,
+		bytes.NewReader(b),
+		client.API.Ingest.PutPipeline.WithContext(context.Background()),
+		client.API.Ingest.PutPipeline.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when add ingest pipeline %s: %s", name, res.String())
+	}
+
 	return nil
 }
+
+// getIngestPipeline return the ingest pipeline or nil if not exist
+func getIngestPipeline(name string, meta interface{}) (pipeline *IngestPipeline, err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Ingest.GetPipeline(
+		client.API.Ingest.GetPipeline.WithPipelineID(name),
+		client.API.Ingest.GetPipeline.WithContext(context.Background()),
+		client.API.Ingest.GetPipeline.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get ingest pipeline %s: %s", name, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	pipelines := IngestPipelineGetResponse{}
+	if err = json.Unmarshal(b, &pipelines); err != nil {
+		return nil, err
+	}
+
+	p, ok := pipelines[name]
+	if !ok {
+		return nil, nil
+	}
+
+	return &p, nil
+}
+
+// normalizeIngestPipeline remove the empty values that Elasticsearch not return
+func normalizeIngestPipeline(pipeline *IngestPipeline) {
+	if len(pipeline.Meta) == 0 {
+		pipeline.Meta = nil
+	}
+	if pipeline.Deprecated != nil && !*pipeline.Deprecated {
+		pipeline.Deprecated = nil
+	}
+	if len(pipeline.Processors) == 0 {
+		pipeline.Processors = nil
+	}
+	if len(pipeline.OnFailure) == 0 {
+		pipeline.OnFailure = nil
+	}
+}
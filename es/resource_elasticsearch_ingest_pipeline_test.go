@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"testing"
 
-	eshandler "github.com/disaster37/es-handler/v8"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
@@ -53,8 +51,7 @@ func testCheckElasticsearchIngestPipelineExists(name string) resource.TestCheckF
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(eshandler.ElasticsearchHandler)
-		pipeline, err := client.IngestPipelineGet(rs.Primary.ID)
+		pipeline, err := getIngestPipeline(rs.Primary.ID, meta)
 		if err != nil {
 			return err
 		}
@@ -77,8 +74,7 @@ func testCheckElasticsearchIngestPipelineDestroy(s *terraform.State) error {
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(eshandler.ElasticsearchHandler)
-		pipeline, err := client.IngestPipelineGet(rs.Primary.ID)
+		pipeline, err := getIngestPipeline(rs.Primary.ID, meta)
 		if err != nil {
 			return err
 		}
@@ -118,12 +114,26 @@ resource "elasticsearch_ingest_pipeline" "test" {
   pipeline 	  = <<EOF
 {
 	"description" : "My optional pipeline description",
+	"version": 2,
+	"_meta": {
+		"owner": "terraform"
+	},
 	"processors" : [
 		{
 			"set" : {
 				"description" : "My optional processor description",
 				"field": "my-keyword-field",
-				"value": "foo"
+				"value": "foo",
+				"if": "ctx.message != null",
+				"ignore_failure": true
+			}
+		}
+	],
+	"on_failure": [
+		{
+			"set": {
+				"field": "error.message",
+				"value": "{{ _ingest.on_failure_message }}"
 			}
 		}
 	]
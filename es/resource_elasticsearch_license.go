@@ -30,8 +30,9 @@ func resourceElasticsearchLicense() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"license": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
 				DiffSuppressFunc: func(k, oldValue, newValue string, d *schema.ResourceData) bool {
 					var err error
 
@@ -0,0 +1,181 @@
+// Manage the SLM or ILM operation mode in elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/slm-api-start.html
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ilm-start.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// LifecycleStatusResponse is the SLM or ILM status API response
+type LifecycleStatusResponse struct {
+	OperationMode string `json:"operation_mode"`
+}
+
+// resourceElasticsearchLifecycleOperationMode handle the SLM and ILM start / stop API call
+func resourceElasticsearchLifecycleOperationMode() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchLifecycleOperationModeCreate,
+		Read:   resourceElasticsearchLifecycleOperationModeRead,
+		Update: resourceElasticsearchLifecycleOperationModeUpdate,
+		Delete: resourceElasticsearchLifecycleOperationModeDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"service": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"slm", "ilm"}, false),
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"operation_mode": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceElasticsearchLifecycleOperationModeCreate start or stop the service
+func resourceElasticsearchLifecycleOperationModeCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	service := d.Get("service").(string)
+
+	if err = setLifecycleOperationMode(service, d.Get("enabled").(bool), meta); err != nil {
+		return err
+	}
+	d.SetId(service)
+
+	return resourceElasticsearchLifecycleOperationModeRead(d, meta)
+}
+
+// resourceElasticsearchLifecycleOperationModeUpdate start or stop the service
+func resourceElasticsearchLifecycleOperationModeUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	if err = setLifecycleOperationMode(d.Id(), d.Get("enabled").(bool), meta); err != nil {
+		return err
+	}
+
+	return resourceElasticsearchLifecycleOperationModeRead(d, meta)
+}
+
+// resourceElasticsearchLifecycleOperationModeRead read the operation mode of the service
+func resourceElasticsearchLifecycleOperationModeRead(d *schema.ResourceData, meta interface{}) (err error) {
+	id := d.Id()
+
+	if id != "slm" && id != "ilm" {
+		return errors.Errorf("Lifecycle service must be slm or ilm, got %s", id)
+	}
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	var res *esapi.Response
+	if id == "slm" {
+		res, err = client.API.SlmGetStatus(
+			client.API.SlmGetStatus.WithContext(context.Background()),
+			client.API.SlmGetStatus.WithPretty(),
+		)
+	} else {
+		res, err = client.API.ILM.GetStatus(
+			client.API.ILM.GetStatus.WithContext(context.Background()),
+			client.API.ILM.GetStatus.WithPretty(),
+		)
+	}
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when get %s status: %s", id, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	status := &LifecycleStatusResponse{}
+	if err = json.Unmarshal(b, status); err != nil {
+		return err
+	}
+
+	if err = d.Set("service", id); err != nil {
+		return err
+	}
+	if err = d.Set("operation_mode", status.OperationMode); err != nil {
+		return err
+	}
+	// STOPPING is considered as disabled, the service stop once the running operations end
+	if err = d.Set("enabled", status.OperationMode == "RUNNING"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resourceElasticsearchLifecycleOperationModeDelete start the service, it's the default operation mode
+func resourceElasticsearchLifecycleOperationModeDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	if err = setLifecycleOperationMode(d.Id(), true, meta); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// setLifecycleOperationMode start or stop the SLM or ILM service
+func setLifecycleOperationMode(service string, enabled bool, meta interface{}) (err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+
+	var res *esapi.Response
+	switch {
+	case service == "slm" && enabled:
+		res, err = client.API.SlmStart(
+			client.API.SlmStart.WithContext(context.Background()),
+			client.API.SlmStart.WithPretty(),
+		)
+	case service == "slm":
+		res, err = client.API.SlmStop(
+			client.API.SlmStop.WithContext(context.Background()),
+			client.API.SlmStop.WithPretty(),
+		)
+	case enabled:
+		res, err = client.API.ILM.Start(
+			client.API.ILM.Start.WithContext(context.Background()),
+			client.API.ILM.Start.WithPretty(),
+		)
+	default:
+		res, err = client.API.ILM.Stop(
+			client.API.ILM.Stop.WithContext(context.Background()),
+			client.API.ILM.Stop.WithPretty(),
+		)
+	}
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when set %s enabled to %t: %s", service, enabled, res.String())
+	}
+
+	log.Infof("Set %s enabled to %t successfully", service, enabled)
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+)
+
+func TestAccElasticsearchLifecycleOperationMode(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchLifecycleOperationModeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchLifecycleOperationMode,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchLifecycleOperationModeExists("elasticsearch_lifecycle_operation_mode.test"),
+					resource.TestCheckResourceAttr("elasticsearch_lifecycle_operation_mode.test", "enabled", "false"),
+				),
+			},
+			{
+				Config: testElasticsearchLifecycleOperationModeUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchLifecycleOperationModeExists("elasticsearch_lifecycle_operation_mode.test"),
+					resource.TestCheckResourceAttr("elasticsearch_lifecycle_operation_mode.test", "operation_mode", "RUNNING"),
+				),
+			},
+			{
+				ResourceName:      "elasticsearch_lifecycle_operation_mode.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testCheckElasticsearchLifecycleOperationModeExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No lifecycle operation mode ID is set")
+		}
+
+		status, err := testGetSlmStatus()
+		if err != nil {
+			return err
+		}
+		if status.OperationMode != rs.Primary.Attributes["operation_mode"] && status.OperationMode != "STOPPED" {
+			return errors.Errorf("SLM operation mode is %s, expected %s", status.OperationMode, rs.Primary.Attributes["operation_mode"])
+		}
+
+		return nil
+	}
+}
+
+func testCheckElasticsearchLifecycleOperationModeDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "elasticsearch_lifecycle_operation_mode" {
+			continue
+		}
+
+		status, err := testGetSlmStatus()
+		if err != nil {
+			return err
+		}
+		if status.OperationMode != "RUNNING" {
+			return fmt.Errorf("SLM is not running after destroy: %s", status.OperationMode)
+		}
+	}
+
+	return nil
+}
+
+func testGetSlmStatus() (status *LifecycleStatusResponse, err error) {
+	meta := testAccProvider.Meta()
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.SlmGetStatus(
+		client.API.SlmGetStatus.WithContext(context.Background()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, errors.Errorf("Error when get SLM status: %s", res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	status = &LifecycleStatusResponse{}
+	if err = json.Unmarshal(b, status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+var testElasticsearchLifecycleOperationMode = `
+resource "elasticsearch_lifecycle_operation_mode" "test" {
+  service = "slm"
+  enabled = false
+}
+`
+
+var testElasticsearchLifecycleOperationModeUpdate = `
+resource "elasticsearch_lifecycle_operation_mode" "test" {
+  service = "slm"
+  enabled = true
+}
+`
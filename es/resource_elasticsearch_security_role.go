@@ -3,19 +3,75 @@
 // Supported version:
 //  - v6
 //  - v7
+//  - v8
 
 package es
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"reflect"
+	"strings"
 
 	eshandler "github.com/disaster37/es-handler/v8"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
+// SecurityRole is the role object with the fields not provided by es-handler
+type SecurityRole struct {
+	Description       string                                         `json:"description,omitempty"`
+	RunAs             []string                                       `json:"run_as,omitempty"`
+	Cluster           []string                                       `json:"cluster,omitempty"`
+	Indices           []SecurityRoleIndicesPermissions               `json:"indices,omitempty"`
+	RemoteIndices     []SecurityRoleRemoteIndicesPermissions         `json:"remote_indices,omitempty"`
+	RemoteCluster     []SecurityRoleRemoteClusterPermissions         `json:"remote_cluster,omitempty"`
+	Applications      []eshandler.XPackSecurityApplicationPrivileges `json:"applications,omitempty"`
+	Global            map[string]any                                 `json:"global,omitempty"`
+	Metadata          map[string]any                                 `json:"metadata,omitempty"`
+	TransientMetadata map[string]any                                 `json:"transient_metadata,omitempty"`
+}
+
+// SecurityRoleIndicesPermissions is the indices permission object
+type SecurityRoleIndicesPermissions struct {
+	Names                  []string                   `json:"names"`
+	Privileges             []string                   `json:"privileges"`
+	FieldSecurity          *SecurityRoleFieldSecurity `json:"field_security,omitempty"`
+	Query                  string                     `json:"query,omitempty"`
+	AllowRestrictedIndices bool                       `json:"allow_restricted_indices"`
+}
+
+// SecurityRoleFieldSecurity is the field level security object
+type SecurityRoleFieldSecurity struct {
+	Grant  []string `json:"grant,omitempty"`
+	Except []string `json:"except,omitempty"`
+}
+
+// SecurityRoleRemoteIndicesPermissions is the indices permission object on remote clusters
+type SecurityRoleRemoteIndicesPermissions struct {
+	SecurityRoleIndicesPermissions
+	Clusters []string `json:"clusters"`
+}
+
+// SecurityRoleRemoteClusterPermissions is the cluster permission object on remote clusters
+type SecurityRoleRemoteClusterPermissions struct {
+	Privileges []string `json:"privileges"`
+	Clusters   []string `json:"clusters"`
+}
+
+// SecurityRoleGetResponse is the get role API response
+type SecurityRoleGetResponse map[string]SecurityRole
+
+// SecurityBuiltinPrivilegesResponse is the get builtin privileges API response
+type SecurityBuiltinPrivilegesResponse struct {
+	Cluster []string `json:"cluster"`
+	Index   []string `json:"index"`
+}
+
 // resourceElasticsearchSecurityRole handle the role API call
 func resourceElasticsearchSecurityRole() *schema.Resource {
 	return &schema.Resource{
@@ -24,10 +80,203 @@ func resourceElasticsearchSecurityRole() *schema.Resource {
 		Update: resourceElasticsearchSecurityRoleUpdate,
 		Delete: resourceElasticsearchSecurityRoleDelete,
 
+		CustomizeDiff: resourceElasticsearchSecurityRoleCustomizeDiffPrivileges,
+
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceElasticsearchSecurityRoleV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceElasticsearchSecurityRoleStateUpgradeV0,
+				Version: 0,
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"clear_cache_on_change": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"clear_cache_realms": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"cluster": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"run_as": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"global": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"metadata": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentJSON,
+			},
+			"indices": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: securityRoleIndicesSchema(),
+				},
+			},
+			"remote_indices": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: securityRoleRemoteIndicesSchema(),
+				},
+			},
+			"remote_cluster": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"clusters": {
+							Type:     schema.TypeSet,
+							Required: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"privileges": {
+							Type:     schema.TypeSet,
+							Required: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"applications": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"application": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"privileges": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"resources": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// securityRoleIndicesSchema return the schema of indices permission
+func securityRoleIndicesSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"names": {
+			Type:     schema.TypeSet,
+			Required: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"privileges": {
+			Type:     schema.TypeSet,
+			Required: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"query": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			DiffSuppressFunc: suppressEquivalentJSON,
+			ValidateFunc:     validateRoleQuery,
+		},
+		"field_security": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"grant": {
+						Type:     schema.TypeSet,
+						Optional: true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+					"except": {
+						Type:     schema.TypeSet,
+						Optional: true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+				},
+			},
+		},
+		"allow_restricted_indices": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}
+}
+
+// securityRoleRemoteIndicesSchema return the schema of indices permission on remote clusters
+func securityRoleRemoteIndicesSchema() map[string]*schema.Schema {
+	s := securityRoleIndicesSchema()
+	s["clusters"] = &schema.Schema{
+		Type:     schema.TypeSet,
+		Required: true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+
+	return s
+}
+
+// resourceElasticsearchSecurityRoleV0 is the schema before field_security become a block
+func resourceElasticsearchSecurityRoleV0() *schema.Resource {
+	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -119,6 +368,139 @@ func resourceElasticsearchSecurityRole() *schema.Resource {
 	}
 }
 
+// resourceElasticsearchSecurityRoleStateUpgradeV0 convert the field_security JSON string to block
+func resourceElasticsearchSecurityRoleStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	rawIndices, ok := rawState["indices"].([]interface{})
+	if !ok {
+		return rawState, nil
+	}
+	for _, rawIndice := range rawIndices {
+		indice, ok := rawIndice.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		indice["allow_restricted_indices"] = false
+
+		fieldSecurityStr, ok := indice["field_security"].(string)
+		if !ok || fieldSecurityStr == "" {
+			indice["field_security"] = []interface{}{}
+			continue
+		}
+		fieldSecurity := &SecurityRoleFieldSecurity{}
+		if err := json.Unmarshal([]byte(fieldSecurityStr), fieldSecurity); err != nil {
+			return nil, errors.Wrap(err, "Error when upgrade field_security of role")
+		}
+		indice["field_security"] = flattenFieldSecurity(fieldSecurity)
+	}
+
+	return rawState, nil
+}
+
+// resourceElasticsearchSecurityRoleCustomizeDiffPrivileges check that cluster and index privileges are builtin privileges
+// The action names like indices:data/read/* are not checked
+func resourceElasticsearchSecurityRoleCustomizeDiffPrivileges(ctx context.Context, d *schema.ResourceDiff, meta interface{}) (err error) {
+	if !d.HasChanges("cluster", "indices", "remote_indices") {
+		return nil
+	}
+
+	privileges, err := getBuiltinPrivileges(meta)
+	if err != nil {
+		return err
+	}
+	if privileges == nil {
+		return nil
+	}
+
+	if d.NewValueKnown("cluster") {
+		for _, privilege := range convertArrayInterfaceToArrayString(d.Get("cluster").(*schema.Set).List()) {
+			if err = checkBuiltinPrivilege("Cluster", privilege, privileges.Cluster); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, key := range []string{"indices", "remote_indices"} {
+		if !d.NewValueKnown(key) {
+			continue
+		}
+		for _, raw := range d.Get(key).(*schema.Set).List() {
+			m := raw.(map[string]interface{})
+			for _, privilege := range convertArrayInterfaceToArrayString(m["privileges"].(*schema.Set).List()) {
+				if err = checkBuiltinPrivilege("Index", privilege, privileges.Index); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkBuiltinPrivilege return error with the closest builtin privilege if the privilege not exist
+func checkBuiltinPrivilege(kind string, privilege string, builtins []string) error {
+	if privilege == "" || strings.Contains(privilege, ":") {
+		return nil
+	}
+	for _, builtin := range builtins {
+		if builtin == privilege {
+			return nil
+		}
+	}
+
+	if closest := getClosestString(privilege, builtins); closest != "" {
+		return errors.Errorf("%s privilege %s is not a builtin privilege, did you mean %s?", kind, privilege, closest)
+	}
+	return errors.Errorf("%s privilege %s is not a builtin privilege. The builtin privileges are: %s", kind, privilege, strings.Join(builtins, ", "))
+}
+
+// getBuiltinPrivileges return the builtin cluster and index privileges, or nil if the API can't be used
+// The result is cached on provider to call the API only one time per cluster
+func getBuiltinPrivileges(meta interface{}) (privileges *SecurityBuiltinPrivilegesResponse, err error) {
+	provider := meta.(*providerMeta)
+	provider.builtinPrivilegesLock.Lock()
+	defer provider.builtinPrivilegesLock.Unlock()
+
+	if provider.builtinPrivilegesLoaded {
+		return provider.builtinPrivileges, nil
+	}
+
+	client := provider.Client()
+	res, err := client.API.Security.GetBuiltinPrivileges(
+		client.API.Security.GetBuiltinPrivileges.WithContext(context.Background()),
+		client.API.Security.GetBuiltinPrivileges.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		// The user can't read builtin privileges or the API not exist, so the privileges are not checked
+		if res.StatusCode == 403 || res.StatusCode == 404 {
+			fmt.Printf("[WARN] Can't get builtin privileges, the role privileges are not checked: %s", res.String())
+			log.Warnf("Can't get builtin privileges, the role privileges are not checked: %s", res.String())
+			provider.builtinPrivilegesLoaded = true
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get builtin privileges: %s", res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	privileges = &SecurityBuiltinPrivilegesResponse{}
+	if err = json.Unmarshal(b, privileges); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Get builtin privileges: %s", string(b))
+
+	provider.builtinPrivileges = privileges
+	provider.builtinPrivilegesLoaded = true
+
+	return privileges, nil
+}
+
 // resourceElasticsearchSecurityRoleCreate create new role in Elasticsearch
 func resourceElasticsearchSecurityRoleCreate(d *schema.ResourceData, meta interface{}) (err error) {
 	name := d.Get("name").(string)
@@ -129,6 +511,10 @@ func resourceElasticsearchSecurityRoleCreate(d *schema.ResourceData, meta interf
 	}
 	d.SetId(name)
 
+	if err = clearSecurityCacheOnChange(d, []string{name}, nil, meta); err != nil {
+		return err
+	}
+
 	log.Infof("Created role %s successfully", name)
 
 	return resourceElasticsearchSecurityRoleRead(d, meta)
@@ -141,9 +527,7 @@ func resourceElasticsearchSecurityRoleRead(d *schema.ResourceData, meta interfac
 
 	log.Debugf("Role id:  %s", id)
 
-	client := meta.(eshandler.ElasticsearchHandler)
-	role, err := client.RoleGet(id)
-
+	role, err := getRole(id, meta)
 	if err != nil {
 		return err
 	}
@@ -158,16 +542,22 @@ func resourceElasticsearchSecurityRoleRead(d *schema.ResourceData, meta interfac
 		return err
 	}
 
-	flattenIndices, err := flattenIndicesMapping(role.Indices)
-	if err != nil {
-		return err
-	}
-	if err = d.Set("indices", flattenIndices); err != nil {
+	if err = d.Set("indices", flattenIndicesMapping(role.Indices)); err != nil {
 		return fmt.Errorf("error setting indices: %w", err)
 	}
 	if err = d.Set("cluster", role.Cluster); err != nil {
 		return fmt.Errorf("error setting cluster: %w", err)
 	}
+	if err = d.Set("description", role.Description); err != nil {
+		return err
+	}
+
+	if err = d.Set("remote_indices", flattenRemoteIndicesMapping(role.RemoteIndices)); err != nil {
+		return fmt.Errorf("error setting remote_indices: %w", err)
+	}
+	if err = d.Set("remote_cluster", flattenRemoteClustersMapping(role.RemoteCluster)); err != nil {
+		return fmt.Errorf("error setting remote_cluster: %w", err)
+	}
 
 	if err = d.Set("applications", flattenApplicationsMapping(role.Applications)); err != nil {
 		return fmt.Errorf("error setting applications: %w", err)
@@ -203,11 +593,20 @@ func resourceElasticsearchSecurityRoleRead(d *schema.ResourceData, meta interfac
 
 // resourceElasticsearchSecurityRoleUpdate update existing role in Elasticsearch
 func resourceElasticsearchSecurityRoleUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	// Nothing to update when only the cache fields change
+	if !d.HasChangesExcept(securityCacheFields...) {
+		return resourceElasticsearchSecurityRoleRead(d, meta)
+	}
+
 	err = createRole(d, meta)
 	if err != nil {
 		return err
 	}
 
+	if err = clearSecurityCacheOnChange(d, []string{d.Id()}, nil, meta); err != nil {
+		return err
+	}
+
 	log.Infof("Updated role %s successfully", d.Id())
 
 	return resourceElasticsearchSecurityRoleRead(d, meta)
@@ -223,6 +622,11 @@ func resourceElasticsearchSecurityRoleDelete(d *schema.ResourceData, meta interf
 	if err = client.RoleDelete(id); err != nil {
 		return err
 	}
+	// The role is already deleted, so it must be removed from state even if the cache can't be cleared
+	if err = clearSecurityCacheOnChange(d, []string{id}, nil, meta); err != nil {
+		fmt.Printf("[WARN] Error when clear cache after delete role %s: %s", id, err.Error())
+		log.Warnf("Error when clear cache after delete role %s: %s", id, err.Error())
+	}
 
 	d.SetId("")
 
@@ -234,40 +638,88 @@ func resourceElasticsearchSecurityRoleDelete(d *schema.ResourceData, meta interf
 // createRole create or update role in Elasticsearch
 func createRole(d *schema.ResourceData, meta interface{}) (err error) {
 	name := d.Get("name").(string)
-	indices := buildRolesIndicesPermissions(d.Get("indices").(*schema.Set).List())
-	applications := buildRolesApplicationPrivileges(d.Get("applications").(*schema.Set).List())
-	cluster := convertArrayInterfaceToArrayString(d.Get("cluster").(*schema.Set).List())
-	global := optionalInterfaceJSON(d.Get("global").(string))
-	runAs := convertArrayInterfaceToArrayString(d.Get("run_as").(*schema.Set).List())
-	metadata := optionalInterfaceJSON(d.Get("metadata").(string))
-
-	client := meta.(eshandler.ElasticsearchHandler)
 
-	data := &eshandler.XPackSecurityRole{
-		Cluster:      cluster,
-		Applications: applications,
-		Indices:      indices,
-		RunAs:        runAs,
+	data := &SecurityRole{
+		Description:   d.Get("description").(string),
+		Cluster:       convertArrayInterfaceToArrayString(d.Get("cluster").(*schema.Set).List()),
+		RunAs:         convertArrayInterfaceToArrayString(d.Get("run_as").(*schema.Set).List()),
+		Indices:       buildRolesIndicesPermissions(d.Get("indices").(*schema.Set).List()),
+		RemoteIndices: buildRolesRemoteIndicesPermissions(d.Get("remote_indices").(*schema.Set).List()),
+		RemoteCluster: buildRolesRemoteClusterPermissions(d.Get("remote_cluster").(*schema.Set).List()),
+		Applications:  buildRolesApplicationPrivileges(d.Get("applications").(*schema.Set).List()),
 	}
-
-	if global != nil {
-		data.Global = global.(map[string]interface{})
+	if data.Global, err = convertRawJsonTopMapString(d.Get("global").(string)); err != nil {
+		return errors.Wrap(err, "Error when read global")
 	}
-	if metadata != nil {
-		data.Metadata = metadata.(map[string]interface{})
+	if data.Metadata, err = convertRawJsonTopMapString(d.Get("metadata").(string)); err != nil {
+		return errors.Wrap(err, "Error when read metadata")
 	}
 
-	if err = client.RoleUpdate(name, data); err != nil {
+	b, err := json.Marshal(data)
+	if err != nil {
 		return err
 	}
 
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Security.PutRole(
+		name,
+		bytes.NewReader(b),
+		client.API.Security.PutRole.WithContext(context.Background()),
+		client.API.Security.PutRole.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when add role %s: %s", name, res.String())
+	}
+
 	return nil
 }
 
-// buildRolesIndicesPermissions convert list to list of RoleIndicesPermissions objects
-func buildRolesIndicesPermissions(raws []interface{}) []eshandler.XPackSecurityIndicesPermissions {
+// getRole return the role or nil if not found
+// es-handler not handle remote privileges, field security and description, so we call the API directly
+func getRole(name string, meta interface{}) (role *SecurityRole, err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Security.GetRole(
+		client.API.Security.GetRole.WithName(name),
+		client.API.Security.GetRole.WithContext(context.Background()),
+		client.API.Security.GetRole.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get role %s: %s", name, res.String())
+	}
 
-	rolesIndicesPermissions := make([]eshandler.XPackSecurityIndicesPermissions, 0, len(raws))
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	roles := SecurityRoleGetResponse{}
+	if err = json.Unmarshal(b, &roles); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Get role %s: %s", name, string(b))
+
+	if tmp, ok := roles[name]; ok {
+		return &tmp, nil
+	}
+
+	return nil, nil
+}
+
+// buildRolesIndicesPermissions convert list to list of SecurityRoleIndicesPermissions objects
+func buildRolesIndicesPermissions(raws []interface{}) []SecurityRoleIndicesPermissions {
+
+	rolesIndicesPermissions := make([]SecurityRoleIndicesPermissions, 0, len(raws))
 
 	for _, raw := range raws {
 		m := raw.(map[string]interface{})
@@ -275,20 +727,74 @@ func buildRolesIndicesPermissions(raws []interface{}) []eshandler.XPackSecurityI
 		if len(m["names"].(*schema.Set).List()) == 0 {
 			continue
 		}
-		roleIndicesPermisions := eshandler.XPackSecurityIndicesPermissions{
-			Names:         convertArrayInterfaceToArrayString(m["names"].(*schema.Set).List()),
-			Privileges:    convertArrayInterfaceToArrayString(m["privileges"].(*schema.Set).List()),
-			Query:         m["query"].(string),
-			FieldSecurity: optionalInterfaceJSON(m["field_security"].(string)),
-		}
 
-		rolesIndicesPermissions = append(rolesIndicesPermissions, roleIndicesPermisions)
+		rolesIndicesPermissions = append(rolesIndicesPermissions, buildRoleIndicesPermissions(m))
 
 	}
 
 	return rolesIndicesPermissions
 }
 
+// buildRoleIndicesPermissions convert map to SecurityRoleIndicesPermissions object
+func buildRoleIndicesPermissions(m map[string]interface{}) SecurityRoleIndicesPermissions {
+	roleIndicesPermissions := SecurityRoleIndicesPermissions{
+		Names:                  convertArrayInterfaceToArrayString(m["names"].(*schema.Set).List()),
+		Privileges:             convertArrayInterfaceToArrayString(m["privileges"].(*schema.Set).List()),
+		Query:                  m["query"].(string),
+		AllowRestrictedIndices: m["allow_restricted_indices"].(bool),
+	}
+
+	if raws := m["field_security"].([]interface{}); len(raws) > 0 {
+		roleIndicesPermissions.FieldSecurity = &SecurityRoleFieldSecurity{}
+		if fieldSecurity, ok := raws[0].(map[string]interface{}); ok {
+			roleIndicesPermissions.FieldSecurity.Grant = convertArrayInterfaceToArrayString(fieldSecurity["grant"].(*schema.Set).List())
+			roleIndicesPermissions.FieldSecurity.Except = convertArrayInterfaceToArrayString(fieldSecurity["except"].(*schema.Set).List())
+		}
+	}
+
+	return roleIndicesPermissions
+}
+
+// buildRolesRemoteIndicesPermissions convert list to list of SecurityRoleRemoteIndicesPermissions objects
+func buildRolesRemoteIndicesPermissions(raws []interface{}) []SecurityRoleRemoteIndicesPermissions {
+	rolesRemoteIndicesPermissions := make([]SecurityRoleRemoteIndicesPermissions, 0, len(raws))
+
+	for _, raw := range raws {
+		m := raw.(map[string]interface{})
+		// Mitigeate bug https://github.com/hashicorp/terraform-plugin-sdk/issues/895
+		if len(m["names"].(*schema.Set).List()) == 0 {
+			continue
+		}
+
+		rolesRemoteIndicesPermissions = append(rolesRemoteIndicesPermissions, SecurityRoleRemoteIndicesPermissions{
+			SecurityRoleIndicesPermissions: buildRoleIndicesPermissions(m),
+			Clusters:                       convertArrayInterfaceToArrayString(m["clusters"].(*schema.Set).List()),
+		})
+	}
+
+	return rolesRemoteIndicesPermissions
+}
+
+// buildRolesRemoteClusterPermissions convert list to list of SecurityRoleRemoteClusterPermissions objects
+func buildRolesRemoteClusterPermissions(raws []interface{}) []SecurityRoleRemoteClusterPermissions {
+	rolesRemoteClusterPermissions := make([]SecurityRoleRemoteClusterPermissions, 0, len(raws))
+
+	for _, raw := range raws {
+		m := raw.(map[string]interface{})
+		// Mitigeate bug https://github.com/hashicorp/terraform-plugin-sdk/issues/895
+		if len(m["clusters"].(*schema.Set).List()) == 0 {
+			continue
+		}
+
+		rolesRemoteClusterPermissions = append(rolesRemoteClusterPermissions, SecurityRoleRemoteClusterPermissions{
+			Privileges: convertArrayInterfaceToArrayString(m["privileges"].(*schema.Set).List()),
+			Clusters:   convertArrayInterfaceToArrayString(m["clusters"].(*schema.Set).List()),
+		})
+	}
+
+	return rolesRemoteClusterPermissions
+}
+
 // buildRolesApplicationPrivileges convert list to list of RoleApplicationPrivileges objects
 func buildRolesApplicationPrivileges(raws []interface{}) []eshandler.XPackSecurityApplicationPrivileges {
 	rolesApplicationPrivileges := make([]eshandler.XPackSecurityApplicationPrivileges, 0, len(raws))
@@ -313,51 +819,84 @@ func buildRolesApplicationPrivileges(raws []interface{}) []eshandler.XPackSecuri
 	return rolesApplicationPrivileges
 }
 
-func flattenIndiceMapping(indice eshandler.XPackSecurityIndicesPermissions) (map[string]interface{}, error) {
-	if reflect.ValueOf(indice).IsZero() {
-		return nil, nil
+func flattenIndiceMapping(indice SecurityRoleIndicesPermissions) map[string]interface{} {
+	if len(indice.Names) == 0 {
+		return nil
 	}
 
 	tfMap := make(map[string]interface{})
 	tfMap["names"] = indice.Names
 	tfMap["privileges"] = indice.Privileges
+	tfMap["query"] = indice.Query
+	tfMap["field_security"] = flattenFieldSecurity(indice.FieldSecurity)
+	tfMap["allow_restricted_indices"] = indice.AllowRestrictedIndices
 
-	if indice.Query != "" {
-		queryB, err := json.Marshal(indice.Query)
-		if err != nil {
-			return nil, err
-		}
-		tfMap["query"] = string(queryB)
+	return tfMap
+}
+
+func flattenIndicesMapping(indices []SecurityRoleIndicesPermissions) []interface{} {
+	if indices == nil {
+		return nil
 	}
 
-	if indice.FieldSecurity != nil {
-		fiedlSecurityB, err := json.Marshal(indice.FieldSecurity)
-		if err != nil {
-			return nil, err
+	tfList := make([]interface{}, 0, len(indices))
+
+	for _, indice := range indices {
+		if tfMap := flattenIndiceMapping(indice); tfMap != nil {
+			tfList = append(tfList, tfMap)
 		}
-		tfMap["field_security"] = string(fiedlSecurityB)
 	}
 
-	return tfMap, nil
+	return tfList
+
 }
 
-func flattenIndicesMapping(indices []eshandler.XPackSecurityIndicesPermissions) ([]interface{}, error) {
-	if indices == nil {
-		return nil, nil
+func flattenRemoteIndicesMapping(remoteIndices []SecurityRoleRemoteIndicesPermissions) []interface{} {
+	if remoteIndices == nil {
+		return nil
 	}
 
-	tfList := make([]interface{}, 0, len(indices))
+	tfList := make([]interface{}, 0, len(remoteIndices))
 
-	for _, indice := range indices {
-		flattenIndices, err := flattenIndiceMapping(indice)
-		if err != nil {
-			return nil, err
+	for _, remoteIndice := range remoteIndices {
+		tfMap := flattenIndiceMapping(remoteIndice.SecurityRoleIndicesPermissions)
+		if tfMap == nil {
+			continue
 		}
-		tfList = append(tfList, flattenIndices)
+		tfMap["clusters"] = remoteIndice.Clusters
+		tfList = append(tfList, tfMap)
+	}
+
+	return tfList
+}
+
+func flattenFieldSecurity(fieldSecurity *SecurityRoleFieldSecurity) []interface{} {
+	if fieldSecurity == nil {
+		return []interface{}{}
 	}
 
-	return tfList, nil
+	return []interface{}{
+		map[string]interface{}{
+			"grant":  fieldSecurity.Grant,
+			"except": fieldSecurity.Except,
+		},
+	}
+}
 
+func flattenRemoteClustersMapping(remoteClusters []SecurityRoleRemoteClusterPermissions) []interface{} {
+	if remoteClusters == nil {
+		return nil
+	}
+
+	tfList := make([]interface{}, 0, len(remoteClusters))
+	for _, remoteCluster := range remoteClusters {
+		tfList = append(tfList, map[string]interface{}{
+			"clusters":   remoteCluster.Clusters,
+			"privileges": remoteCluster.Privileges,
+		})
+	}
+
+	return tfList
 }
 
 func flattenApplicationMapping(application eshandler.XPackSecurityApplicationPrivileges) map[string]interface{} {
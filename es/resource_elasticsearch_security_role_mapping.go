@@ -3,18 +3,56 @@
 // Supported version:
 //  - v6
 //  - v7
+//  - v8
 
 package es
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
 
 	eshandler "github.com/disaster37/es-handler/v8"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	olivere "github.com/olivere/elastic/v7"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
+// SecurityRoleMapping is the role mapping object with role templates
+type SecurityRoleMapping struct {
+	Enabled       bool                              `json:"enabled"`
+	Roles         []string                          `json:"roles,omitempty"`
+	RoleTemplates []SecurityRoleMappingRoleTemplate `json:"role_templates,omitempty"`
+	Rules         map[string]any                    `json:"rules"`
+	Metadata      map[string]any                    `json:"metadata,omitempty"`
+}
+
+// SecurityRoleMappingRoleTemplate is the role template object
+// The template is returned as JSON string by the API
+type SecurityRoleMappingRoleTemplate struct {
+	Template json.RawMessage `json:"template"`
+	Format   string          `json:"format,omitempty"`
+}
+
+// SecurityRoleMappingTemplate is the mustache script of role template
+type SecurityRoleMappingTemplate struct {
+	Source string `json:"source"`
+}
+
+// SecurityRoleMappingGetResponse is the get role mapping API response
+type SecurityRoleMappingGetResponse map[string]SecurityRoleMapping
+
+// roleMappingRuleMaxDepth is the number of any, all and except levels that can be nested on rule block
+const roleMappingRuleMaxDepth = 3
+
+// roleMappingRuleFields is the user fields that can be used on field rule, with the metadata.* fields
+var roleMappingRuleFields = []string{"username", "dn", "groups", "realm.name"}
+
 // resourceElasticsearchSecurityRoleMapping handle role mapping API call
 func resourceElasticsearchSecurityRoleMapping() *schema.Resource {
 	return &schema.Resource{
@@ -23,6 +61,8 @@ func resourceElasticsearchSecurityRoleMapping() *schema.Resource {
 		Update: resourceElasticsearchSecurityRoleMappingUpdate,
 		Delete: resourceElasticsearchSecurityRoleMappingDelete,
 
+		CustomizeDiff: resourceElasticsearchSecurityRoleMappingCustomizeDiffRule,
+
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -33,6 +73,18 @@ func resourceElasticsearchSecurityRoleMapping() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"clear_cache_on_change": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"clear_cache_realms": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			"enabled": {
 				Type:     schema.TypeBool,
 				Default:  true,
@@ -40,15 +92,43 @@ func resourceElasticsearchSecurityRoleMapping() *schema.Resource {
 			},
 			"rules": {
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				ExactlyOneOf:     []string{"rules", "rule"},
 				DiffSuppressFunc: suppressEquivalentJSON,
+				ValidateFunc:     validateRoleMappingRules,
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem:     roleMappingRuleSchema(roleMappingRuleMaxDepth),
 			},
 			"roles": {
 				Type: schema.TypeSet,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
-				Required: true,
+				Optional:     true,
+				ExactlyOneOf: []string{"roles", "role_templates"},
+			},
+			"role_templates": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"template": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateMustacheTemplate,
+						},
+						"format": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "string",
+							ValidateFunc: validation.StringInSlice([]string{"string", "json"}, false),
+						},
+					},
+				},
 			},
 			"metadata": {
 				Type:             schema.TypeString,
@@ -59,6 +139,75 @@ func resourceElasticsearchSecurityRoleMapping() *schema.Resource {
 	}
 }
 
+// roleMappingRuleSchema return the schema of rule block
+// Terraform not support recursive schema, so any, all and except can be nested only until depth
+func roleMappingRuleSchema(depth int) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"field": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validateRoleMappingRuleField,
+					},
+					"values": {
+						Type:     schema.TypeList,
+						Required: true,
+						MinItems: 1,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if depth > 0 {
+		s["any"] = &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     roleMappingRuleSchema(depth - 1),
+		}
+		s["all"] = &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     roleMappingRuleSchema(depth - 1),
+		}
+		s["except"] = &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem:     roleMappingRuleSchema(depth - 1),
+		}
+	}
+
+	return &schema.Resource{
+		Schema: s,
+	}
+}
+
+// resourceElasticsearchSecurityRoleMappingCustomizeDiffRule check that each rule block has exactly one of field, any, all or except
+func resourceElasticsearchSecurityRoleMappingCustomizeDiffRule(ctx context.Context, d *schema.ResourceDiff, meta interface{}) (err error) {
+	if !d.HasChange("rule") || !d.NewValueKnown("rule") {
+		return nil
+	}
+
+	raws := d.Get("rule").([]interface{})
+	if len(raws) == 0 {
+		return nil
+	}
+	if _, err = expandRoleMappingRule(raws); err != nil {
+		return errors.Wrap(err, "Error on rule")
+	}
+
+	return nil
+}
+
 // resourceElasticsearchSecurityRoleMappingCreate  create new role mapping in Elasticsearch
 func resourceElasticsearchSecurityRoleMappingCreate(d *schema.ResourceData, meta interface{}) (err error) {
 	name := d.Get("name").(string)
@@ -68,6 +217,11 @@ func resourceElasticsearchSecurityRoleMappingCreate(d *schema.ResourceData, meta
 		return err
 	}
 	d.SetId(name)
+
+	if err = clearSecurityCacheOnChange(d, nil, nil, meta); err != nil {
+		return err
+	}
+
 	log.Infof("Created role mapping %s successfully", name)
 
 	return resourceElasticsearchSecurityRoleMappingRead(d, meta)
@@ -80,9 +234,7 @@ func resourceElasticsearchSecurityRoleMappingRead(d *schema.ResourceData, meta i
 
 	log.Debugf("Role mapping id:  %s", id)
 
-	client := meta.(eshandler.ElasticsearchHandler)
-
-	rm, err := client.RoleMappingGet(id)
+	rm, err := getRoleMapping(id, meta)
 	if err != nil {
 		return err
 	}
@@ -102,13 +254,42 @@ func resourceElasticsearchSecurityRoleMappingRead(d *schema.ResourceData, meta i
 	if err = d.Set("roles", rm.Roles); err != nil {
 		return err
 	}
-	flattenRules, err := convertInterfaceToJsonString(rm.Rules)
+	roleTemplates, err := flattenRoleTemplates(rm.RoleTemplates)
 	if err != nil {
 		return err
 	}
-	if err = d.Set("rules", flattenRules); err != nil {
+	if err = d.Set("role_templates", roleTemplates); err != nil {
 		return err
 	}
+	// Use typed rule only if it's already used, else use raw rules like on import because it's lossless
+	// Fallback to raw rules when the rule can't be converted to rule block
+	var rule map[string]interface{}
+	if _, useRule := d.GetOk("rule"); useRule {
+		if rule, err = flattenRoleMappingRule(rm.Rules, roleMappingRuleMaxDepth); err != nil {
+			fmt.Printf("[WARN] Rules of role mapping %s can't be converted to rule block, use rules instead: %s\n", id, err.Error())
+			log.Warnf("Rules of role mapping %s can't be converted to rule block, use rules instead: %s", id, err.Error())
+			rule = nil
+		}
+	}
+	if rule != nil {
+		if err = d.Set("rule", []interface{}{rule}); err != nil {
+			return err
+		}
+		if err = d.Set("rules", ""); err != nil {
+			return err
+		}
+	} else {
+		flattenRules, err := convertInterfaceToJsonString(rm.Rules)
+		if err != nil {
+			return err
+		}
+		if err = d.Set("rules", flattenRules); err != nil {
+			return err
+		}
+		if err = d.Set("rule", nil); err != nil {
+			return err
+		}
+	}
 	flattenMetadata, err := convertInterfaceToJsonString(rm.Metadata)
 	if err != nil {
 		return err
@@ -123,11 +304,20 @@ func resourceElasticsearchSecurityRoleMappingRead(d *schema.ResourceData, meta i
 
 // resourceElasticsearchSecurityRoleMappingUpdate update existing role mapping in Elasticsearch
 func resourceElasticsearchSecurityRoleMappingUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	// Nothing to update when only the cache fields change
+	if !d.HasChangesExcept(securityCacheFields...) {
+		return resourceElasticsearchSecurityRoleMappingRead(d, meta)
+	}
+
 	err = createRoleMapping(d, meta)
 	if err != nil {
 		return err
 	}
 
+	if err = clearSecurityCacheOnChange(d, nil, nil, meta); err != nil {
+		return err
+	}
+
 	log.Infof("Updated role mapping %s successfully", d.Id())
 
 	return resourceElasticsearchSecurityRoleMappingRead(d, meta)
@@ -143,6 +333,11 @@ func resourceElasticsearchSecurityRoleMappingDelete(d *schema.ResourceData, meta
 	if err = client.RoleMappingDelete(id); err != nil {
 		return err
 	}
+	// The role mapping is already deleted, so it must be removed from state even if the cache can't be cleared
+	if err = clearSecurityCacheOnChange(d, nil, nil, meta); err != nil {
+		fmt.Printf("[WARN] Error when clear cache after delete role mapping %s: %s", id, err.Error())
+		log.Warnf("Error when clear cache after delete role mapping %s: %s", id, err.Error())
+	}
 
 	d.SetId("")
 
@@ -159,27 +354,307 @@ func createRoleMapping(d *schema.ResourceData, meta interface{}) (err error) {
 	rulesStr := d.Get("rules").(string)
 	metadataStr := d.Get("metadata").(string)
 
-	client := meta.(eshandler.ElasticsearchHandler)
-
-	rules, err := convertRawJsonTopMapString(rulesStr)
-	if err != nil {
+	var rules map[string]any
+	if rulesStr != "" {
+		if rules, err = convertRawJsonTopMapString(rulesStr); err != nil {
+			return err
+		}
+	} else if rules, err = expandRoleMappingRule(d.Get("rule").([]interface{})); err != nil {
 		return err
 	}
 	metadata, err := convertRawJsonTopMapString(metadataStr)
 	if err != nil {
 		return err
 	}
+	roleTemplates, err := buildRoleTemplates(d.Get("role_templates").([]interface{}))
+	if err != nil {
+		return err
+	}
 
-	data := &olivere.XPackSecurityRoleMapping{
-		Enabled:  enabled,
-		Roles:    roles,
-		Rules:    rules,
-		Metadata: metadata,
+	data := &SecurityRoleMapping{
+		Enabled:       enabled,
+		Roles:         roles,
+		RoleTemplates: roleTemplates,
+		Rules:         rules,
+		Metadata:      metadata,
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
 	}
 
-	if err = client.RoleMappingUpdate(name, data); err != nil {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Security.PutRoleMapping(
+		name,
+		bytes.NewReader(b),
+		client.API.Security.PutRoleMapping.WithContext(context.Background()),
+		client.API.Security.PutRoleMapping.WithPretty(),
+	)
+	if err != nil {
 		return err
 	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when add role mapping %s: %s", name, res.String())
+	}
+
+	return nil
+}
+
+// getRoleMapping return the role mapping or nil if not found
+// es-handler not handle role templates, so we call the API directly
+func getRoleMapping(name string, meta interface{}) (rm *SecurityRoleMapping, err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Security.GetRoleMapping(
+		client.API.Security.GetRoleMapping.WithName(name),
+		client.API.Security.GetRoleMapping.WithContext(context.Background()),
+		client.API.Security.GetRoleMapping.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get role mapping %s: %s", name, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	roleMappings := SecurityRoleMappingGetResponse{}
+	if err = json.Unmarshal(b, &roleMappings); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Get role mapping %s: %s", name, string(b))
+
+	if tmp, ok := roleMappings[name]; ok {
+		return &tmp, nil
+	}
+
+	return nil, nil
+}
+
+// buildRoleTemplates convert list to list of SecurityRoleMappingRoleTemplate objects
+func buildRoleTemplates(raws []interface{}) ([]SecurityRoleMappingRoleTemplate, error) {
+	roleTemplates := make([]SecurityRoleMappingRoleTemplate, 0, len(raws))
+
+	for _, raw := range raws {
+		m := raw.(map[string]interface{})
+		template, err := json.Marshal(&SecurityRoleMappingTemplate{
+			Source: m["template"].(string),
+		})
+		if err != nil {
+			return nil, err
+		}
+		roleTemplates = append(roleTemplates, SecurityRoleMappingRoleTemplate{
+			Template: template,
+			Format:   m["format"].(string),
+		})
+	}
+
+	return roleTemplates, nil
+}
+
+// flattenRoleTemplates convert list of SecurityRoleMappingRoleTemplate objects to list
+// The template can be a JSON string or a JSON object
+func flattenRoleTemplates(roleTemplates []SecurityRoleMappingRoleTemplate) ([]interface{}, error) {
+	tfList := make([]interface{}, 0, len(roleTemplates))
+
+	for _, roleTemplate := range roleTemplates {
+		raw := []byte(roleTemplate.Template)
+		templateStr := ""
+		if err := json.Unmarshal(raw, &templateStr); err == nil {
+			raw = []byte(templateStr)
+		}
+		template := &SecurityRoleMappingTemplate{}
+		if err := json.Unmarshal(raw, template); err != nil {
+			return nil, errors.Wrapf(err, "Error when read role template %s", string(roleTemplate.Template))
+		}
+
+		format := roleTemplate.Format
+		if format == "" {
+			format = "string"
+		}
+		tfList = append(tfList, map[string]interface{}{
+			"template": template.Source,
+			"format":   format,
+		})
+	}
+
+	return tfList, nil
+}
+
+// expandRoleMappingRule convert rule block to rule DSL
+func expandRoleMappingRule(raws []interface{}) (rule map[string]any, err error) {
+	if len(raws) == 0 || raws[0] == nil {
+		return nil, errors.New("the rule must have one of field, any, all or except")
+	}
+	m := raws[0].(map[string]interface{})
+
+	rule = map[string]any{}
+	if fields, ok := m["field"].([]interface{}); ok && len(fields) > 0 && fields[0] != nil {
+		field := fields[0].(map[string]interface{})
+		values := convertArrayInterfaceToArrayString(field["values"].([]interface{}))
+		if len(values) == 1 {
+			rule["field"] = map[string]any{field["name"].(string): values[0]}
+		} else {
+			rule["field"] = map[string]any{field["name"].(string): values}
+		}
+	}
+	for _, key := range []string{"any", "all"} {
+		items, ok := m[key].([]interface{})
+		if !ok || len(items) == 0 {
+			continue
+		}
+		rules := make([]any, 0, len(items))
+		for _, item := range items {
+			subRule, err := expandRoleMappingRule([]interface{}{item})
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, subRule)
+		}
+		rule[key] = rules
+	}
+	if items, ok := m["except"].([]interface{}); ok && len(items) > 0 {
+		if rule["except"], err = expandRoleMappingRule(items); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = checkRoleMappingRule(rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// flattenRoleMappingRule convert rule DSL to rule block
+func flattenRoleMappingRule(rule map[string]any, depth int) (map[string]interface{}, error) {
+	if err := checkRoleMappingRule(rule); err != nil {
+		return nil, err
+	}
+	if _, isField := rule["field"]; !isField && depth == 0 {
+		return nil, errors.Errorf("the rule has more than %d nested levels of any, all or except, use rules instead of rule", roleMappingRuleMaxDepth)
+	}
+
+	tfMap := map[string]interface{}{}
+	for key, value := range rule {
+		switch key {
+		case "field":
+			for name, raw := range value.(map[string]any) {
+				// Rule block only support string values, number, boolean and null values need rules to keep their type
+				items, isList := raw.([]any)
+				if !isList {
+					items = []any{raw}
+				}
+				values := make([]string, 0, len(items))
+				for _, item := range items {
+					value, ok := item.(string)
+					if !ok {
+						return nil, errors.Errorf("the value %v of user field %s is not a string, use rules instead of rule", item, name)
+					}
+					values = append(values, value)
+				}
+				tfMap["field"] = []interface{}{
+					map[string]interface{}{
+						"name":   name,
+						"values": values,
+					},
+				}
+			}
+		case "any", "all":
+			items := value.([]any)
+			rules := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				subRule, err := flattenRoleMappingRule(item.(map[string]any), depth-1)
+				if err != nil {
+					return nil, err
+				}
+				rules = append(rules, subRule)
+			}
+			tfMap[key] = rules
+		case "except":
+			subRule, err := flattenRoleMappingRule(value.(map[string]any), depth-1)
+			if err != nil {
+				return nil, err
+			}
+			tfMap["except"] = []interface{}{subRule}
+		}
+	}
+
+	return tfMap, nil
+}
+
+// checkRoleMappingRule check the structure of rule DSL
+// Each rule must have exactly one of field, any, all or except, and the field rule must have exactly one known user field
+func checkRoleMappingRule(rule map[string]any) error {
+	if len(rule) != 1 {
+		keys := make([]string, 0, len(rule))
+		for key := range rule {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return errors.Errorf("the rule must have exactly one of field, any, all or except, got [%s]", strings.Join(keys, ", "))
+	}
+
+	for key, value := range rule {
+		switch key {
+		case "field":
+			field, ok := value.(map[string]any)
+			if !ok || len(field) != 1 {
+				return errors.Errorf("the field rule must have exactly one user field, got %v", value)
+			}
+			for name := range field {
+				if err := checkRoleMappingRuleField(name); err != nil {
+					return err
+				}
+			}
+		case "any", "all":
+			items, ok := value.([]any)
+			if !ok || len(items) == 0 {
+				return errors.Errorf("the %s rule must be a non empty list of rules", key)
+			}
+			for _, item := range items {
+				subRule, ok := item.(map[string]any)
+				if !ok {
+					return errors.Errorf("the %s rule must be a list of rules, got %v", key, item)
+				}
+				if err := checkRoleMappingRule(subRule); err != nil {
+					return err
+				}
+			}
+		case "except":
+			subRule, ok := value.(map[string]any)
+			if !ok {
+				return errors.Errorf("the except rule must be a rule, got %v", value)
+			}
+			if err := checkRoleMappingRule(subRule); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("the rule %s not exist, it must be one of field, any, all or except", key)
+		}
+	}
 
 	return nil
 }
+
+// checkRoleMappingRuleField check the user field can be used on field rule
+func checkRoleMappingRuleField(name string) error {
+	if strings.HasPrefix(name, "metadata.") && len(name) > len("metadata.") {
+		return nil
+	}
+	for _, field := range roleMappingRuleFields {
+		if field == name {
+			return nil
+		}
+	}
+
+	return errors.Errorf("the user field %s not exist, it must be one of %s or metadata.*", name, strings.Join(roleMappingRuleFields, ", "))
+}
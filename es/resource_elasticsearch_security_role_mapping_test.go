@@ -2,9 +2,9 @@ package es
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
-	eshandler "github.com/disaster37/es-handler/v8"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
@@ -32,9 +32,34 @@ func TestAccElasticsearchSecurityRoleMapping(t *testing.T) {
 				),
 			},
 			{
-				ResourceName:      "elasticsearch_role_mapping.test",
-				ImportState:       true,
-				ImportStateVerify: true,
+				Config: testElasticsearchSecurityRoleMappingRoleTemplates,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSecurityRoleMappingExists("elasticsearch_role_mapping.test"),
+					resource.TestCheckResourceAttr("elasticsearch_role_mapping.test", "roles.#", "0"),
+					resource.TestCheckResourceAttr("elasticsearch_role_mapping.test", "role_templates.#", "2"),
+					resource.TestCheckResourceAttr("elasticsearch_role_mapping.test", "role_templates.0.template", "{{#tojson}}groups{{/tojson}}"),
+					resource.TestCheckResourceAttr("elasticsearch_role_mapping.test", "role_templates.0.format", "json"),
+					resource.TestCheckResourceAttr("elasticsearch_role_mapping.test", "role_templates.1.format", "string"),
+					resource.TestCheckResourceAttr("elasticsearch_role_mapping.test", "rule.0.all.#", "3"),
+				),
+			},
+			{
+				Config:      testElasticsearchSecurityRoleMappingBadRules,
+				ExpectError: regexp.MustCompile("the field rule must have exactly one user field"),
+			},
+			{
+				Config: testElasticsearchSecurityRoleMappingRawRules,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSecurityRoleMappingExists("elasticsearch_role_mapping.test"),
+					resource.TestCheckResourceAttr("elasticsearch_role_mapping.test", "rule.#", "0"),
+					resource.TestCheckResourceAttrSet("elasticsearch_role_mapping.test", "rules"),
+				),
+			},
+			{
+				ResourceName:            "elasticsearch_role_mapping.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"clear_cache_on_change", "clear_cache_realms"},
 			},
 		},
 	})
@@ -52,8 +77,7 @@ func testCheckElasticsearchSecurityRoleMappingExists(name string) resource.TestC
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(eshandler.ElasticsearchHandler)
-		rm, err := client.RoleMappingGet(rs.Primary.ID)
+		rm, err := getRoleMapping(rs.Primary.ID, meta)
 		if err != nil {
 			return err
 		}
@@ -76,8 +100,7 @@ func testCheckElasticsearchSecurityRoleMappingDestroy(s *terraform.State) error
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(eshandler.ElasticsearchHandler)
-		rm, err := client.RoleMappingGet(rs.Primary.ID)
+		rm, err := getRoleMapping(rs.Primary.ID, meta)
 		if err != nil {
 			return err
 		}
@@ -121,3 +144,108 @@ resource "elasticsearch_role_mapping" "test" {
 EOF
 }
 `
+
+var testElasticsearchSecurityRoleMappingRoleTemplates = `
+resource "elasticsearch_role_mapping" "test" {
+  name 		= "terraform-test"
+  clear_cache_on_change = true
+  clear_cache_realms    = ["default_native"]
+  enabled 	= "true"
+  role_templates {
+	template = "{{#tojson}}groups{{/tojson}}"
+	format   = "json"
+  }
+  role_templates {
+	template = "{{metadata.department}}_user"
+  }
+  rule {
+	all {
+	  field {
+		name   = "realm.name"
+		values = ["ldap1"]
+	  }
+	}
+	all {
+	  any {
+		field {
+		  name   = "groups"
+		  values = ["cn=admins,dc=example,dc=com", "cn=admins2,dc=example,dc=com"]
+		}
+	  }
+	  any {
+		field {
+		  name   = "metadata.department"
+		  values = ["it"]
+		}
+	  }
+	}
+	all {
+	  except {
+		field {
+		  name   = "username"
+		  values = ["guest"]
+		}
+	  }
+	}
+  }
+}
+`
+
+var testElasticsearchSecurityRoleMappingBadRules = `
+resource "elasticsearch_role_mapping" "test" {
+  name 		= "terraform-test"
+  enabled 	= "true"
+  roles 	= ["superuser"]
+  rules 	= <<EOF
+{
+	"field": {
+		"groups": "cn=admins,dc=example,dc=com",
+		"username": "admin"
+	}
+}
+EOF
+}
+`
+
+var testElasticsearchSecurityRoleMappingRawRules = `
+resource "elasticsearch_role_mapping" "test" {
+  name 		= "terraform-test"
+  enabled 	= "true"
+  roles 	= ["superuser"]
+  rules 	= <<EOF
+{
+	"all": [
+		{
+			"field": {
+				"metadata.level": 3
+			}
+		},
+		{
+			"field": {
+				"metadata.active": true
+			}
+		},
+		{
+			"any": [
+				{
+					"all": [
+						{
+							"except": {
+								"any": [
+									{
+										"field": {
+											"username": "guest"
+										}
+									}
+								]
+							}
+						}
+					]
+				}
+			]
+		}
+	]
+}
+EOF
+}
+`
@@ -2,6 +2,7 @@ package es
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	eshandler "github.com/disaster37/es-handler/v8"
@@ -31,6 +32,49 @@ func TestAccElasticsearchSecurityRole(t *testing.T) {
 					testCheckElasticsearchSecurityRoleExists("elasticsearch_role.test"),
 				),
 			},
+			{
+				Config: testElasticsearchSecurityRoleDocumentLevelSecurity,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSecurityRoleExists("elasticsearch_role.test"),
+					testCheckElasticsearchSecurityRoleFieldSecurity("elasticsearch_role.test", "app-*", []string{"message", "user.*"}, []string{"user.password"}),
+				),
+			},
+			{
+				Config:      testElasticsearchSecurityRoleBadQuery,
+				ExpectError: regexp.MustCompile("has invalid mustache template"),
+			},
+			{
+				Config:      testElasticsearchSecurityRoleBadPrivilege,
+				ExpectError: regexp.MustCompile("did you mean monitor_ml"),
+			},
+			{
+				ResourceName:            "elasticsearch_role.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"clear_cache_on_change"},
+			},
+		},
+	})
+}
+
+func TestAccElasticsearchSecurityRoleRemote(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheckVersion(t, 8, 15)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckElasticsearchSecurityRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchSecurityRoleRemote,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSecurityRoleExists("elasticsearch_role.test"),
+					resource.TestCheckResourceAttr("elasticsearch_role.test", "description", "Role for cross cluster search"),
+					resource.TestCheckResourceAttr("elasticsearch_role.test", "remote_indices.#", "1"),
+					resource.TestCheckResourceAttr("elasticsearch_role.test", "remote_cluster.#", "1"),
+				),
+			},
 			{
 				ResourceName:      "elasticsearch_role.test",
 				ImportState:       true,
@@ -52,8 +96,7 @@ func testCheckElasticsearchSecurityRoleExists(name string) resource.TestCheckFun
 
 		meta := testAccProvider.Meta()
 
-		client := meta.(eshandler.ElasticsearchHandler)
-		role, err := client.RoleGet(rs.Primary.ID)
+		role, err := getRole(rs.Primary.ID, meta)
 		if err != nil {
 			return err
 		}
@@ -65,6 +108,42 @@ func testCheckElasticsearchSecurityRoleExists(name string) resource.TestCheckFun
 	}
 }
 
+func testCheckElasticsearchSecurityRoleFieldSecurity(name string, index string, grant []string, except []string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+
+		meta := testAccProvider.Meta()
+
+		role, err := getRole(rs.Primary.ID, meta)
+		if err != nil {
+			return err
+		}
+		if role == nil {
+			return errors.Errorf("Role %s not found", rs.Primary.ID)
+		}
+
+		for _, indice := range role.Indices {
+			if len(indice.Names) != 1 || indice.Names[0] != index {
+				continue
+			}
+			if indice.FieldSecurity == nil {
+				return errors.Errorf("Role %s has no field security on %s", rs.Primary.ID, index)
+			}
+			if diff, err := eshandler.StandardDiff(indice.FieldSecurity, &SecurityRoleFieldSecurity{Grant: grant, Except: except}, logEntry, nil); err != nil {
+				return err
+			} else if diff != "" {
+				return errors.Errorf("Role %s has not the expected field security on %s: %s", rs.Primary.ID, index, diff)
+			}
+			return nil
+		}
+
+		return errors.Errorf("Role %s has no indices permission on %s", rs.Primary.ID, index)
+	}
+}
+
 func testCheckElasticsearchSecurityRoleDestroy(s *terraform.State) error {
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "elasticsearch_role" {
@@ -121,3 +200,85 @@ resource "elasticsearch_role" "test" {
   cluster = ["all"]
 }
 `
+
+var testElasticsearchSecurityRoleDocumentLevelSecurity = `
+resource "elasticsearch_role" "test" {
+  name = "terraform-test"
+  clear_cache_on_change = true
+  indices {
+	  names = ["logstash-*"]
+	  privileges = ["write"]
+	  allow_restricted_indices = false
+  }
+  indices {
+	  names = ["app-*"]
+	  privileges = ["read"]
+	  query = jsonencode({
+		  template = {
+			  source = {
+				  term = {
+					  owner = "{{_user.username}}"
+				  }
+			  }
+		  }
+	  })
+	  field_security {
+		  grant = ["message", "user.*"]
+		  except = ["user.password"]
+	  }
+  }
+  cluster = ["all"]
+}
+`
+
+var testElasticsearchSecurityRoleBadQuery = `
+resource "elasticsearch_role" "test" {
+  name = "terraform-test"
+  indices {
+	  names = ["app-*"]
+	  privileges = ["read"]
+	  query = jsonencode({
+		  template = {
+			  source = {
+				  term = {
+					  owner = "{{_user.username"
+				  }
+			  }
+		  }
+	  })
+  }
+  cluster = ["all"]
+}
+`
+
+var testElasticsearchSecurityRoleBadPrivilege = `
+resource "elasticsearch_role" "test" {
+  name = "terraform-test"
+  indices {
+	  names = ["app-*"]
+	  privileges = ["read"]
+  }
+  cluster = ["monitor_ml_job"]
+}
+`
+
+var testElasticsearchSecurityRoleRemote = `
+resource "elasticsearch_role" "test" {
+  name = "terraform-test"
+  description = "Role for cross cluster search"
+  indices {
+	  names = ["logstash-*"]
+	  privileges = ["read"]
+  }
+  remote_indices {
+	  clusters = ["remote-*"]
+	  names = ["logstash-*"]
+	  privileges = ["read", "view_index_metadata"]
+  }
+  remote_cluster {
+	  clusters = ["remote-*"]
+	  privileges = ["monitor_enrich"]
+  }
+  cluster = ["monitor"]
+}
+`
@@ -33,6 +33,18 @@ func resourceElasticsearchSecurityUser() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"clear_cache_on_change": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"clear_cache_realms": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			"email": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -42,12 +54,28 @@ func resourceElasticsearchSecurityUser() *schema.Resource {
 				Optional: true,
 			},
 			"password": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"password_wo"},
 			},
 			"password_hash": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"password_wo"},
+			},
+			"password_wo": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				WriteOnly:    true,
+				RequiredWith: []string{"password_wo_version"},
+			},
+			"password_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"password_wo"},
 			},
 			"enabled": {
 				Type:     schema.TypeBool,
@@ -80,6 +108,10 @@ func resourceElasticsearchSecurityUserCreate(d *schema.ResourceData, meta interf
 	}
 	d.SetId(username)
 
+	if err = clearSecurityCacheOnChange(d, nil, []string{username}, meta); err != nil {
+		return err
+	}
+
 	log.Infof("Created user %s successfully", username)
 
 	return resourceElasticsearchSecurityUserRead(d, meta)
@@ -136,6 +168,11 @@ func resourceElasticsearchSecurityUserRead(d *schema.ResourceData, meta interfac
 
 // resourceElasticsearchSecurityUserUpdate update existing user in Elasticsearch
 func resourceElasticsearchSecurityUserUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	// Nothing to update when only the cache fields change
+	if !d.HasChangesExcept(securityCacheFields...) {
+		return resourceElasticsearchSecurityUserRead(d, meta)
+	}
+
 	id := d.Id()
 	enabled := d.Get("enabled").(bool)
 	email := d.Get("email").(string)
@@ -145,6 +182,15 @@ func resourceElasticsearchSecurityUserUpdate(d *schema.ResourceData, meta interf
 	roles := convertArrayInterfaceToArrayString(d.Get("roles").(*schema.Set).List())
 	metadata := optionalInterfaceJSON(d.Get("metadata").(string))
 
+	// The write only password is never on state, so we use the version to know if it change
+	passwordWo, err := getWriteOnlyString(d, "password_wo")
+	if err != nil {
+		return err
+	}
+	if passwordWo != "" {
+		password = passwordWo
+	}
+
 	client := meta.(eshandler.ElasticsearchHandler)
 
 	data := &olivere.XPackSecurityPutUserRequest{
@@ -158,7 +204,7 @@ func resourceElasticsearchSecurityUserUpdate(d *schema.ResourceData, meta interf
 	}
 
 	// Provide password only if it change
-	if d.HasChange("password") || d.HasChange("password_hash") {
+	if d.HasChanges("password", "password_hash", "password_wo_version") {
 		data.Password = password
 		data.PasswordHash = passwordHash
 	}
@@ -166,6 +212,11 @@ func resourceElasticsearchSecurityUserUpdate(d *schema.ResourceData, meta interf
 	if err = client.UserUpdate(id, data); err != nil {
 		return err
 	}
+	if err = clearSecurityCacheOnChange(d, nil, []string{id}, meta); err != nil {
+		return err
+	}
+
+	log.Infof("Updated user %s successfully", id)
 
 	return resourceElasticsearchSecurityUserRead(d, meta)
 }
@@ -181,6 +232,11 @@ func resourceElasticsearchSecurityUserDelete(d *schema.ResourceData, meta interf
 	if err = client.UserDelete(id); err != nil {
 		return err
 	}
+	// The user is already deleted, so it must be removed from state even if the cache can't be cleared
+	if err = clearSecurityCacheOnChange(d, nil, []string{id}, meta); err != nil {
+		fmt.Printf("[WARN] Error when clear cache after delete user %s: %s", id, err.Error())
+		log.Warnf("Error when clear cache after delete user %s: %s", id, err.Error())
+	}
 
 	d.SetId("")
 
@@ -200,6 +256,14 @@ func createUser(d *schema.ResourceData, meta interface{}) (err error) {
 	roles := convertArrayInterfaceToArrayString(d.Get("roles").(*schema.Set).List())
 	metadata := optionalInterfaceJSON(d.Get("metadata").(string))
 
+	passwordWo, err := getWriteOnlyString(d, "password_wo")
+	if err != nil {
+		return err
+	}
+	if passwordWo != "" {
+		password = passwordWo
+	}
+
 	client := meta.(eshandler.ElasticsearchHandler)
 
 	data := &olivere.XPackSecurityPutUserRequest{
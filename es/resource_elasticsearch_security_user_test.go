@@ -1,7 +1,10 @@
 package es
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
+	"strings"
 	"testing"
 
 	eshandler "github.com/disaster37/es-handler/v8"
@@ -31,11 +34,33 @@ func TestAccElasticsearchSecurityUser(t *testing.T) {
 					testCheckElasticsearchSecurityUserExists("elasticsearch_user.test"),
 				),
 			},
+			{
+				Config: testElasticsearchSecurityUserPasswordWriteOnly,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSecurityUserExists("elasticsearch_user.test"),
+					resource.TestCheckNoResourceAttr("elasticsearch_user.test", "password_wo"),
+					resource.TestCheckResourceAttr("elasticsearch_user.test", "password_wo_version", "1"),
+					testCheckElasticsearchSecurityUserAuthenticate("terraform-test", "changeme3"),
+				),
+			},
+			{
+				// Change the password outside Terraform, to check the write only password is not sent again when password_wo_version not change
+				PreConfig: func() {
+					testChangeElasticsearchSecurityUserPassword(t, "terraform-test", "changeme4")
+				},
+				Config: testElasticsearchSecurityUserPasswordWriteOnlyUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSecurityUserExists("elasticsearch_user.test"),
+					resource.TestCheckResourceAttr("elasticsearch_user.test", "full_name", "test3"),
+					resource.TestCheckResourceAttr("elasticsearch_user.test", "password_wo_version", "1"),
+					testCheckElasticsearchSecurityUserAuthenticate("terraform-test", "changeme4"),
+				),
+			},
 			{
 				ResourceName:            "elasticsearch_user.test",
 				ImportState:             true,
 				ImportStateVerify:       true,
-				ImportStateVerifyIgnore: []string{"password", "password_hash"},
+				ImportStateVerifyIgnore: []string{"password", "password_hash", "password_wo_version", "clear_cache_on_change"},
 			},
 		},
 	})
@@ -66,6 +91,51 @@ func testCheckElasticsearchSecurityUserExists(name string) resource.TestCheckFun
 	}
 }
 
+func testCheckElasticsearchSecurityUserAuthenticate(username string, password string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		meta := testAccProvider.Meta()
+
+		// The Authorization header is kept by the transport, so the request is authenticated as the user
+		client := meta.(eshandler.ElasticsearchHandler).Client()
+		res, err := client.API.Security.Authenticate(
+			client.API.Security.Authenticate.WithContext(context.Background()),
+			client.API.Security.Authenticate.WithHeader(map[string]string{
+				"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)),
+			}),
+		)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return errors.Errorf("Error when authenticate user %s: %s", username, res.String())
+		}
+
+		return nil
+	}
+}
+
+func testChangeElasticsearchSecurityUserPassword(t *testing.T, username string, password string) {
+	meta := testAccProvider.Meta()
+	if meta == nil {
+		t.Fatal("Provider is not configured")
+	}
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Security.ChangePassword(
+		strings.NewReader(fmt.Sprintf(`{"password": "%s"}`, password)),
+		client.API.Security.ChangePassword.WithUsername(username),
+		client.API.Security.ChangePassword.WithContext(context.Background()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		t.Fatalf("Error when change password of user %s: %s", username, res.String())
+	}
+}
+
 func testCheckElasticsearchSecurityUserDestroy(s *terraform.State) error {
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "elasticsearch_user" {
@@ -109,7 +179,32 @@ resource "elasticsearch_user" "test" {
   enabled 	= "true"
   email 	= "no@no.no"
   full_name = "test2"
+  clear_cache_on_change = true
   password 	= "changeme2"
   roles 	= ["kibana_user"]
 }
 `
+
+var testElasticsearchSecurityUserPasswordWriteOnly = `
+resource "elasticsearch_user" "test" {
+  username 	= "terraform-test"
+  enabled 	= "true"
+  email 	= "no@no.no"
+  full_name = "test2"
+  password_wo 		  = "changeme3"
+  password_wo_version = 1
+  roles 	= ["kibana_user"]
+}
+`
+
+var testElasticsearchSecurityUserPasswordWriteOnlyUpdate = `
+resource "elasticsearch_user" "test" {
+  username 	= "terraform-test"
+  enabled 	= "true"
+  email 	= "no@no.no"
+  full_name = "test3"
+  password_wo 		  = "changeme3"
+  password_wo_version = 1
+  roles 	= ["kibana_user"]
+}
+`
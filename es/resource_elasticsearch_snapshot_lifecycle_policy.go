@@ -6,14 +6,49 @@
 package es
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 
 	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
+// SnapshotLifecyclePolicy is the SLM policy
+// es-handler omit false boolean on config, so we can't use it to disable include_global_state
+type SnapshotLifecyclePolicy struct {
+	Name       string                               `json:"name"`
+	Schedule   string                               `json:"schedule"`
+	Repository string                               `json:"repository"`
+	Config     json.RawMessage                      `json:"config,omitempty"`
+	Retention  *eshandler.ElasticsearchSLMRetention `json:"retention,omitempty"`
+}
+
+// SnapshotLifecyclePolicyConfig is the config sub section
+type SnapshotLifecyclePolicyConfig struct {
+	Indices            []string `json:"indices,omitempty"`
+	FeatureStates      []string `json:"feature_states,omitempty"`
+	IncludeGlobalState *bool    `json:"include_global_state,omitempty"`
+	IgnoreUnavailable  *bool    `json:"ignore_unavailable,omitempty"`
+	Partial            *bool    `json:"partial,omitempty"`
+}
+
+// SnapshotLifecyclePolicyExecuteResponse is the execute SLM policy API response
+type SnapshotLifecyclePolicyExecuteResponse struct {
+	SnapshotName string `json:"snapshot_name"`
+}
+
+// SnapshotLifecyclePolicyGetResponse is the get SLM policy API response
+type SnapshotLifecyclePolicyGetResponse map[string]struct {
+	Policy SnapshotLifecyclePolicy `json:"policy"`
+}
+
 // resourceElasticsearchSnapshotLifecyclePolicy handle the snapshot lifecycle policy API call
 func resourceElasticsearchSnapshotLifecyclePolicy() *schema.Resource {
 	return &schema.Resource{
@@ -26,6 +61,11 @@ func resourceElasticsearchSnapshotLifecyclePolicy() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		CustomizeDiff: customdiff.All(
+			resourceElasticsearchSnapshotLifecyclePolicyCustomizeDiff,
+			resourceElasticsearchSnapshotLifecyclePolicyExecuteCustomizeDiff,
+		),
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -37,16 +77,19 @@ func resourceElasticsearchSnapshotLifecyclePolicy() *schema.Resource {
 				Required: true,
 			},
 			"schedule": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateCronExpression,
 			},
 			"repository": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			"configs": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Deprecated:    "Use the config block instead",
+				ConflictsWith: []string{"config"},
 				DiffSuppressFunc: func(k, oldValue, newValue string, d *schema.ResourceData) bool {
 					return suppressEquivalentJSONWithExclude(k, oldValue, newValue, d, map[string]any{
 						"ignore_unavailable":   false,
@@ -54,11 +97,86 @@ func resourceElasticsearchSnapshotLifecyclePolicy() *schema.Resource {
 					})
 				},
 			},
+			"execute_on_change": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"executed_snapshot_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"config": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"indices": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"feature_states": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"include_global_state": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"ignore_unavailable": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"partial": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+					},
+				},
+			},
 			"retention": {
 				Type:             schema.TypeString,
 				Optional:         true,
+				Deprecated:       "Use the retention_rule block instead",
+				ConflictsWith:    []string{"retention_rule"},
 				DiffSuppressFunc: suppressEquivalentJSON,
 			},
+			"retention_rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"expire_after": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateTimeValue,
+						},
+						"min_count": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"max_count": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -73,6 +191,13 @@ func resourceElasticsearchSnapshotLifecyclePolicyCreate(d *schema.ResourceData,
 		return err
 	}
 	d.SetId(name)
+
+	if len(d.Get("execute_on_change").(map[string]interface{})) > 0 {
+		if err = executeSnapshotLifecyclePolicy(d, meta); err != nil {
+			return err
+		}
+	}
+
 	return resourceElasticsearchSnapshotLifecyclePolicyRead(d, meta)
 }
 
@@ -82,16 +207,51 @@ func resourceElasticsearchSnapshotLifecyclePolicyUpdate(d *schema.ResourceData,
 	if err != nil {
 		return err
 	}
+
+	if d.HasChange("execute_on_change") && len(d.Get("execute_on_change").(map[string]interface{})) > 0 {
+		if err = executeSnapshotLifecyclePolicy(d, meta); err != nil {
+			return err
+		}
+	}
+
 	return resourceElasticsearchSnapshotLifecyclePolicyRead(d, meta)
 }
 
+// resourceElasticsearchSnapshotLifecyclePolicyCustomizeDiff check the repository exist before apply
+// The check is skipped when the repository is not yet known, like when it reference a repository created on the same apply
+func resourceElasticsearchSnapshotLifecyclePolicyCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) (err error) {
+	if !d.HasChange("repository") || !d.NewValueKnown("repository") {
+		return nil
+	}
+
+	repository := d.Get("repository").(string)
+	client := meta.(eshandler.ElasticsearchHandler)
+	repo, err := client.SnapshotRepositoryGet(repository)
+	if err != nil {
+		return err
+	}
+	if repo == nil {
+		return errors.Errorf("Snapshot repository %s not found. If it's created on the same apply, reference it with the id attribute of elasticsearch_snapshot_repository", repository)
+	}
+
+	return nil
+}
+
+// resourceElasticsearchSnapshotLifecyclePolicyExecuteCustomizeDiff mark the snapshot name as unknown when the policy will be executed
+func resourceElasticsearchSnapshotLifecyclePolicyExecuteCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) (err error) {
+	if d.HasChange("execute_on_change") && len(d.Get("execute_on_change").(map[string]interface{})) > 0 {
+		return d.SetNewComputed("executed_snapshot_name")
+	}
+
+	return nil
+}
+
 // resourceElasticsearchSnapshotLifecyclePolicyRead read snapshot lifecycle policy
 func resourceElasticsearchSnapshotLifecyclePolicyRead(d *schema.ResourceData, meta interface{}) (err error) {
 
 	id := d.Id()
 
-	client := meta.(eshandler.ElasticsearchHandler)
-	policy, err := client.SLMGet(id)
+	policy, err := getSnapshotLifecyclePolicy(id, meta)
 	if err != nil {
 		return err
 	}
@@ -116,19 +276,31 @@ func resourceElasticsearchSnapshotLifecyclePolicyRead(d *schema.ResourceData, me
 		return err
 	}
 
-	flattenConfigs, err := convertInterfaceToJsonString(policy.Config)
-	if err != nil {
-		return err
-	}
-	if err = d.Set("configs", flattenConfigs); err != nil {
-		return err
+	// Keep raw configs if it's what is used
+	if _, useConfigs := d.GetOk("configs"); useConfigs {
+		if err = d.Set("configs", string(policy.Config)); err != nil {
+			return err
+		}
+	} else {
+		flattenConfig, err := flattenSnapshotLifecyclePolicyConfig(policy.Config)
+		if err != nil {
+			return err
+		}
+		if err = d.Set("config", flattenConfig); err != nil {
+			return err
+		}
 	}
 
-	flattenRetention, err := convertInterfaceToJsonString(policy.Retention)
-	if err != nil {
-		return err
-	}
-	if err = d.Set("retention", flattenRetention); err != nil {
+	// Keep raw retention if it's what is used
+	if _, useRetention := d.GetOk("retention"); useRetention {
+		flattenRetention, err := convertInterfaceToJsonString(policy.Retention)
+		if err != nil {
+			return err
+		}
+		if err = d.Set("retention", flattenRetention); err != nil {
+			return err
+		}
+	} else if err = d.Set("retention_rule", flattenSnapshotLifecyclePolicyRetention(policy.Retention)); err != nil {
 		return err
 	}
 
@@ -152,35 +324,209 @@ func resourceElasticsearchSnapshotLifecyclePolicyDelete(d *schema.ResourceData,
 // createSnapshotLifecyclePolicy permit to create or update snapshot lifecycle policy
 func createSnapshotLifecyclePolicy(d *schema.ResourceData, meta interface{}) (err error) {
 	name := d.Get("name").(string)
-	snapshotName := d.Get("snapshot_name").(string)
-	schedule := d.Get("schedule").(string)
-	repository := d.Get("repository").(string)
 	configStr := d.Get("configs").(string)
 	retentionStr := d.Get("retention").(string)
 
-	client := meta.(eshandler.ElasticsearchHandler)
+	policy := &SnapshotLifecyclePolicy{
+		Name:       d.Get("snapshot_name").(string),
+		Schedule:   d.Get("schedule").(string),
+		Repository: d.Get("repository").(string),
+	}
 
-	config := &eshandler.ElasticsearchSLMConfig{}
-	if err = json.Unmarshal([]byte(configStr), config); err != nil {
+	if configStr != "" {
+		policy.Config = json.RawMessage(configStr)
+	} else if policy.Config, err = expandSnapshotLifecyclePolicyConfig(d.Get("config").([]interface{})); err != nil {
 		return err
 	}
 
-	retention := &eshandler.ElasticsearchSLMRetention{}
-	if err = json.Unmarshal([]byte(retentionStr), retention); err != nil {
+	if retentionStr != "" {
+		policy.Retention = &eshandler.ElasticsearchSLMRetention{}
+		if err = json.Unmarshal([]byte(retentionStr), policy.Retention); err != nil {
+			return err
+		}
+	} else {
+		policy.Retention = expandSnapshotLifecyclePolicyRetention(d.Get("retention_rule").([]interface{}))
+	}
+
+	b, err := json.Marshal(policy)
+	if err != nil {
 		return err
 	}
 
-	data := &eshandler.SnapshotLifecyclePolicySpec{
-		Name:       snapshotName,
-		Schedule:   schedule,
-		Repository: repository,
-		Config:     *config,
-		Retention:  retention,
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.SlmPutLifecycle(
+		name,
+		client.API.SlmPutLifecycle.WithBody(bytes.NewReader(b)),
+		client.API.SlmPutLifecycle.WithContext(context.Background()),
+		client.API.SlmPutLifecycle.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when add snapshot lifecycle policy %s: %s", name, res.String())
+	}
+
+	log.Debugf("Add snapshot lifecycle policy %s successfully", name)
+
+	return nil
+}
+
+// executeSnapshotLifecyclePolicy run the policy to take a snapshot immediately
+func executeSnapshotLifecyclePolicy(d *schema.ResourceData, meta interface{}) (err error) {
+	name := d.Id()
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.SlmExecuteLifecycle(
+		name,
+		client.API.SlmExecuteLifecycle.WithContext(context.Background()),
+		client.API.SlmExecuteLifecycle.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when execute snapshot lifecycle policy %s: %s", name, res.String())
 	}
 
-	if err = client.SLMUpdate(name, data); err != nil {
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	execute := &SnapshotLifecyclePolicyExecuteResponse{}
+	if err = json.Unmarshal(b, execute); err != nil {
 		return err
 	}
 
+	if err = d.Set("executed_snapshot_name", execute.SnapshotName); err != nil {
+		return err
+	}
+
+	log.Infof("Execute snapshot lifecycle policy %s successfully, snapshot %s", name, execute.SnapshotName)
+
 	return nil
 }
+
+// getSnapshotLifecyclePolicy return the snapshot lifecycle policy or nil if not exist
+func getSnapshotLifecyclePolicy(name string, meta interface{}) (policy *SnapshotLifecyclePolicy, err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.SlmGetLifecycle(
+		client.API.SlmGetLifecycle.WithPolicyID(name),
+		client.API.SlmGetLifecycle.WithContext(context.Background()),
+		client.API.SlmGetLifecycle.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get snapshot lifecycle policy %s: %s", name, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	policies := SnapshotLifecyclePolicyGetResponse{}
+	if err = json.Unmarshal(b, &policies); err != nil {
+		return nil, err
+	}
+
+	p, ok := policies[name]
+	if !ok {
+		return nil, nil
+	}
+
+	return &p.Policy, nil
+}
+
+// expandSnapshotLifecyclePolicyConfig convert config block to JSON
+func expandSnapshotLifecyclePolicyConfig(raws []interface{}) (json.RawMessage, error) {
+	if len(raws) == 0 || raws[0] == nil {
+		return nil, nil
+	}
+	raw := raws[0].(map[string]interface{})
+
+	includeGlobalState := raw["include_global_state"].(bool)
+	ignoreUnavailable := raw["ignore_unavailable"].(bool)
+	partial := raw["partial"].(bool)
+	config := &SnapshotLifecyclePolicyConfig{
+		Indices:            convertArrayInterfaceToArrayString(raw["indices"].([]interface{})),
+		FeatureStates:      convertArrayInterfaceToArrayString(raw["feature_states"].(*schema.Set).List()),
+		IncludeGlobalState: &includeGlobalState,
+		IgnoreUnavailable:  &ignoreUnavailable,
+		Partial:            &partial,
+	}
+
+	return json.Marshal(config)
+}
+
+// flattenSnapshotLifecyclePolicyConfig convert JSON config to block
+func flattenSnapshotLifecyclePolicyConfig(raw json.RawMessage) ([]interface{}, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return []interface{}{}, nil
+	}
+
+	config := &SnapshotLifecyclePolicyConfig{}
+	if err := json.Unmarshal(raw, config); err != nil {
+		return nil, err
+	}
+
+	// Use Elasticsearch default when not set
+	includeGlobalState := true
+	if config.IncludeGlobalState != nil {
+		includeGlobalState = *config.IncludeGlobalState
+	}
+	ignoreUnavailable := false
+	if config.IgnoreUnavailable != nil {
+		ignoreUnavailable = *config.IgnoreUnavailable
+	}
+	partial := false
+	if config.Partial != nil {
+		partial = *config.Partial
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"indices":              config.Indices,
+			"feature_states":       config.FeatureStates,
+			"include_global_state": includeGlobalState,
+			"ignore_unavailable":   ignoreUnavailable,
+			"partial":              partial,
+		},
+	}, nil
+}
+
+// expandSnapshotLifecyclePolicyRetention convert retention_rule block to API object
+func expandSnapshotLifecyclePolicyRetention(raws []interface{}) *eshandler.ElasticsearchSLMRetention {
+	if len(raws) == 0 || raws[0] == nil {
+		return nil
+	}
+	raw := raws[0].(map[string]interface{})
+
+	return &eshandler.ElasticsearchSLMRetention{
+		ExpireAfter: raw["expire_after"].(string),
+		MinCount:    int64(raw["min_count"].(int)),
+		MaxCount:    int64(raw["max_count"].(int)),
+	}
+}
+
+// flattenSnapshotLifecyclePolicyRetention convert API object to retention_rule block
+func flattenSnapshotLifecyclePolicyRetention(retention *eshandler.ElasticsearchSLMRetention) []interface{} {
+	if retention == nil {
+		return []interface{}{}
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"expire_after": retention.ExpireAfter,
+			"min_count":    retention.MinCount,
+			"max_count":    retention.MaxCount,
+		},
+	}
+}
@@ -2,6 +2,7 @@ package es
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	eshandler "github.com/disaster37/es-handler/v8"
@@ -20,6 +21,14 @@ func TestAccElasticsearchSnapshotLifecyclePolicy(t *testing.T) {
 		Providers:    testAccProviders,
 		CheckDestroy: testCheckElasticsearchSnapshotLifecyclePolicyDestroy,
 		Steps: []resource.TestStep{
+			{
+				Config:      testElasticsearchSnapshotLifecyclePolicyInvalidSchedule,
+				ExpectError: regexp.MustCompile("day_of_month or day_of_week"),
+			},
+			{
+				Config:      testElasticsearchSnapshotLifecyclePolicyMissingRepository,
+				ExpectError: regexp.MustCompile("Snapshot repository terraform-test-missing not found"),
+			},
 			{
 				Config: testElasticsearchSnapshotLifecyclePolicy,
 				Check: resource.ComposeTestCheckFunc(
@@ -30,12 +39,16 @@ func TestAccElasticsearchSnapshotLifecyclePolicy(t *testing.T) {
 				Config: testElasticsearchSnapshotLifecyclePolicyUpdate,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckElasticsearchSnapshotLifecyclePolicyExists("elasticsearch_snapshot_lifecycle_policy.test"),
+					resource.TestCheckResourceAttr("elasticsearch_snapshot_lifecycle_policy.test", "config.0.include_global_state", "false"),
+					resource.TestCheckResourceAttr("elasticsearch_snapshot_lifecycle_policy.test", "retention_rule.0.max_count", "10"),
+					resource.TestCheckResourceAttrSet("elasticsearch_snapshot_lifecycle_policy.test", "executed_snapshot_name"),
 				),
 			},
 			{
-				ResourceName:      "elasticsearch_snapshot_lifecycle_policy.test",
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            "elasticsearch_snapshot_lifecycle_policy.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"execute_on_change", "executed_snapshot_name"},
 			},
 		},
 	})
@@ -109,7 +122,7 @@ resource "elasticsearch_snapshot_lifecycle_policy" "test" {
   name			= "terraform-test"
   snapshot_name = "<daily-snap-{now/d}>"
   schedule 		= "0 30 1 * * ?"
-  repository    = "${elasticsearch_snapshot_repository.test.name}"
+  repository    = elasticsearch_snapshot_repository.test.id
   configs		= <<EOF
 {
 	"indices": ["test-*"],
@@ -141,20 +154,36 @@ resource "elasticsearch_snapshot_lifecycle_policy" "test" {
   name			= "terraform-test"
   snapshot_name = "<daily-snap-{now/d}>"
   schedule 		= "1 30 1 * * ?"
-  repository    = "${elasticsearch_snapshot_repository.test.name}"
-  configs		= <<EOF
-{
-	"indices": ["test-*"],
-	"ignore_unavailable": false,
-	"include_global_state": false
+  repository    = elasticsearch_snapshot_repository.test.id
+  config {
+    indices              = ["test-*"]
+    include_global_state = false
+  }
+  execute_on_change = {
+    run = "1"
+  }
+  retention_rule {
+    expire_after = "7d"
+    min_count    = 5
+    max_count    = 10
+  }
 }
-EOF
-  retention     = <<EOF
-{
-    "expire_after": "7d",
-    "min_count": 5,
-    "max_count": 10
-} 
-EOF
+`
+
+var testElasticsearchSnapshotLifecyclePolicyInvalidSchedule = `
+resource "elasticsearch_snapshot_lifecycle_policy" "test" {
+  name			= "terraform-test"
+  snapshot_name = "<daily-snap-{now/d}>"
+  schedule 		= "0 30 1 * * *"
+  repository    = "test"
+}
+`
+
+var testElasticsearchSnapshotLifecyclePolicyMissingRepository = `
+resource "elasticsearch_snapshot_lifecycle_policy" "test" {
+  name			= "terraform-test"
+  snapshot_name = "<daily-snap-{now/d}>"
+  schedule 		= "0 30 1 * * ?"
+  repository    = "terraform-test-missing"
 }
 `
@@ -7,14 +7,46 @@
 package es
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
 
 	eshandler "github.com/disaster37/es-handler/v8"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	olivere "github.com/olivere/elastic/v7"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
+// SnapshotVerifyRepositoryResponse is the verify repository API response
+type SnapshotVerifyRepositoryResponse struct {
+	Nodes map[string]SnapshotVerifyRepositoryNode `json:"nodes"`
+}
+
+type SnapshotVerifyRepositoryNode struct {
+	Name string `json:"name"`
+}
+
+// SnapshotCleanupRepositoryResponse is the cleanup repository API response
+type SnapshotCleanupRepositoryResponse struct {
+	Results SnapshotCleanupRepositoryResults `json:"results"`
+}
+
+type SnapshotCleanupRepositoryResults struct {
+	DeletedBytes int64 `json:"deleted_bytes"`
+	DeletedBlobs int64 `json:"deleted_blobs"`
+}
+
+// snapshotRepositoryTypes is the list of repository type that can be set with typed block
+var snapshotRepositoryTypes = []string{"fs", "url", "s3", "gcs", "azure", "source"}
+
+// snapshotRepositorySourceDelegateTypes is the list of repository type that can be used behind source repository
+var snapshotRepositorySourceDelegateTypes = []string{"fs", "s3", "gcs", "azure"}
+
 // resourceElasticsearchSnapshotRepository handle the snapshot repository API call
 func resourceElasticsearchSnapshotRepository() *schema.Resource {
 	return &schema.Resource{
@@ -27,23 +59,90 @@ func resourceElasticsearchSnapshotRepository() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		CustomizeDiff: resourceElasticsearchSnapshotRepositoryCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
 			},
+			"verify": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"verified_nodes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"cleanup_on_apply": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"deleted_bytes": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"deleted_blobs": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
 			"type": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: snapshotRepositoryTypes,
 			},
 			"settings": {
-				Type:     schema.TypeMap,
-				Required: true,
+				Type:         schema.TypeMap,
+				Optional:     true,
+				RequiredWith: []string{"type"},
+				ExactlyOneOf: append([]string{"settings"}, snapshotRepositoryTypes...),
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
+			"fs": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem:     snapshotRepositoryFsSchema(),
+			},
+			"url": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem:     snapshotRepositoryURLSchema(),
+			},
+			"s3": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem:     snapshotRepositoryS3Schema(),
+			},
+			"gcs": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem:     snapshotRepositoryGcsSchema(),
+			},
+			"azure": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem:     snapshotRepositoryAzureSchema(),
+			},
+			"source": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem:     snapshotRepositorySourceSchema(),
+			},
 		},
 	}
 }
@@ -58,6 +157,11 @@ func resourceElasticsearchSnapshotRepositoryCreate(d *schema.ResourceData, meta
 		return err
 	}
 	d.SetId(name)
+
+	if err = checkSnapshotRepository(d, meta); err != nil {
+		return err
+	}
+
 	return resourceElasticsearchSnapshotRepositoryRead(d, meta)
 }
 
@@ -67,9 +171,33 @@ func resourceElasticsearchSnapshotRepositoryUpdate(d *schema.ResourceData, meta
 	if err != nil {
 		return err
 	}
+
+	if err = checkSnapshotRepository(d, meta); err != nil {
+		return err
+	}
+
 	return resourceElasticsearchSnapshotRepositoryRead(d, meta)
 }
 
+// resourceElasticsearchSnapshotRepositoryCustomizeDiff mark cleanup result as unknown when repository will be updated
+func resourceElasticsearchSnapshotRepositoryCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) (err error) {
+	if d.Id() == "" || !d.Get("cleanup_on_apply").(bool) {
+		return nil
+	}
+
+	keys := append([]string{"type", "settings", "verify", "cleanup_on_apply"}, snapshotRepositoryTypes...)
+	if d.HasChanges(keys...) {
+		if err = d.SetNewComputed("deleted_bytes"); err != nil {
+			return err
+		}
+		if err = d.SetNewComputed("deleted_blobs"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // resourceElasticsearchSnapshotRepositoryRead read the sanpshot repository
 func resourceElasticsearchSnapshotRepositoryRead(d *schema.ResourceData, meta interface{}) (err error) {
 
@@ -95,9 +223,30 @@ func resourceElasticsearchSnapshotRepositoryRead(d *schema.ResourceData, meta in
 	if err = d.Set("type", repo.Type); err != nil {
 		return err
 	}
-	if err = d.Set("settings", repo.Settings); err != nil {
+
+	// Keep raw settings if it's what is used or if the repository type has no typed block
+	_, useSettings := d.GetOk("settings")
+	if useSettings || !snapshotRepositoryTypeSupported(repo.Type) {
+		if err = d.Set("settings", repo.Settings); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	flattenRepository, err := flattenSnapshotRepository(repo)
+	if err != nil {
 		return err
 	}
+	for _, repositoryType := range snapshotRepositoryTypes {
+		if repositoryType == repo.Type {
+			err = d.Set(repositoryType, flattenRepository)
+		} else {
+			err = d.Set(repositoryType, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -124,6 +273,15 @@ func createSnapshotRepository(d *schema.ResourceData, meta interface{}) (err err
 
 	client := meta.(eshandler.ElasticsearchHandler)
 
+	// Typed block take precedence over raw settings
+	for _, repositoryType := range snapshotRepositoryTypes {
+		if raws := d.Get(repositoryType).([]interface{}); len(raws) > 0 && raws[0] != nil {
+			snapshotType = repositoryType
+			settings = expandSnapshotRepository(repositoryType, raws[0].(map[string]interface{}))
+			break
+		}
+	}
+
 	data := &olivere.SnapshotRepositoryMetaData{
 		Type:     snapshotType,
 		Settings: settings,
@@ -135,3 +293,460 @@ func createSnapshotRepository(d *schema.ResourceData, meta interface{}) (err err
 
 	return nil
 }
+
+// checkSnapshotRepository verify and cleanup the repository after it's created or updated, if needed
+func checkSnapshotRepository(d *schema.ResourceData, meta interface{}) (err error) {
+	if d.Get("verify").(bool) {
+		nodes, err := verifySnapshotRepository(d.Id(), meta)
+		if err != nil {
+			return err
+		}
+		if err = d.Set("verified_nodes", nodes); err != nil {
+			return err
+		}
+	}
+
+	if d.Get("cleanup_on_apply").(bool) {
+		results, err := cleanupSnapshotRepository(d.Id(), meta)
+		if err != nil {
+			return err
+		}
+		if err = d.Set("deleted_bytes", results.DeletedBytes); err != nil {
+			return err
+		}
+		if err = d.Set("deleted_blobs", results.DeletedBlobs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifySnapshotRepository check that all nodes can access the repository and return their names
+func verifySnapshotRepository(name string, meta interface{}) (nodes []string, err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Snapshot.VerifyRepository(
+		name,
+		client.API.Snapshot.VerifyRepository.WithContext(context.Background()),
+		client.API.Snapshot.VerifyRepository.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, errors.Errorf("Error when verify snapshot repository %s, some nodes can't access it: %s", name, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	verify := &SnapshotVerifyRepositoryResponse{}
+	if err = json.Unmarshal(b, verify); err != nil {
+		return nil, err
+	}
+
+	nodes = make([]string, 0, len(verify.Nodes))
+	for _, node := range verify.Nodes {
+		nodes = append(nodes, node.Name)
+	}
+	sort.Strings(nodes)
+
+	log.Debugf("Verify snapshot repository %s successfully: %s", name, string(b))
+
+	return nodes, nil
+}
+
+// cleanupSnapshotRepository remove unreferenced data from the repository
+func cleanupSnapshotRepository(name string, meta interface{}) (results *SnapshotCleanupRepositoryResults, err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Snapshot.CleanupRepository(
+		name,
+		client.API.Snapshot.CleanupRepository.WithContext(context.Background()),
+		client.API.Snapshot.CleanupRepository.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, errors.Errorf("Error when cleanup snapshot repository %s: %s", name, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	cleanup := &SnapshotCleanupRepositoryResponse{}
+	if err = json.Unmarshal(b, cleanup); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Cleanup snapshot repository %s successfully: %s", name, string(b))
+
+	return &cleanup.Results, nil
+}
+
+// snapshotRepositoryCommonSchema return the settings shared by all repository type
+func snapshotRepositoryCommonSchema(readonly bool) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"compress": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"chunk_size": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateByteSize,
+		},
+		"max_snapshot_bytes_per_sec": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "40mb",
+			ValidateFunc: validateByteSize,
+		},
+		"max_restore_bytes_per_sec": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateByteSize,
+		},
+	}
+
+	if readonly {
+		s["readonly"] = &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		}
+	}
+
+	return s
+}
+
+// snapshotRepositoryFsSchema is the settings of shared file system repository
+func snapshotRepositoryFsSchema() *schema.Resource {
+	s := snapshotRepositoryCommonSchema(true)
+	s["location"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+
+	return &schema.Resource{
+		Schema: s,
+	}
+}
+
+// snapshotRepositoryURLSchema is the settings of read-only URL repository
+func snapshotRepositoryURLSchema() *schema.Resource {
+	s := snapshotRepositoryCommonSchema(false)
+	s["url"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+	s["http_max_retries"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Default:      5,
+		ValidateFunc: validation.IntAtLeast(0),
+	}
+	s["http_socket_timeout"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  "50s",
+	}
+	s["max_number_of_snapshots"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Default:      500,
+		ValidateFunc: validation.IntAtLeast(1),
+	}
+
+	return &schema.Resource{
+		Schema: s,
+	}
+}
+
+// snapshotRepositoryS3Schema is the settings of S3 repository
+func snapshotRepositoryS3Schema() *schema.Resource {
+	s := snapshotRepositoryCommonSchema(true)
+	s["bucket"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+	s["client"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  "default",
+	}
+	s["base_path"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+	s["buffer_size"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateByteSize,
+	}
+	s["canned_acl"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  "private",
+		ValidateFunc: validation.StringInSlice([]string{
+			"private",
+			"public-read",
+			"public-read-write",
+			"authenticated-read",
+			"log-delivery-write",
+			"bucket-owner-read",
+			"bucket-owner-full-control",
+		}, false),
+	}
+	s["server_side_encryption"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+	s["storage_class"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  "standard",
+		ValidateFunc: validation.StringInSlice([]string{
+			"standard",
+			"reduced_redundancy",
+			"standard_ia",
+			"onezone_ia",
+			"intelligent_tiering",
+		}, false),
+	}
+
+	return &schema.Resource{
+		Schema: s,
+	}
+}
+
+// snapshotRepositoryGcsSchema is the settings of Google cloud storage repository
+func snapshotRepositoryGcsSchema() *schema.Resource {
+	s := snapshotRepositoryCommonSchema(true)
+	s["bucket"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+	s["client"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  "default",
+	}
+	s["base_path"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+
+	return &schema.Resource{
+		Schema: s,
+	}
+}
+
+// snapshotRepositoryAzureSchema is the settings of Azure repository
+func snapshotRepositoryAzureSchema() *schema.Resource {
+	s := snapshotRepositoryCommonSchema(true)
+	s["container"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  "elasticsearch-snapshots",
+	}
+	s["client"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  "default",
+	}
+	s["base_path"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+	s["location_mode"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      "primary_only",
+		ValidateFunc: validation.StringInSlice([]string{"primary_only", "secondary_only"}, false),
+	}
+
+	return &schema.Resource{
+		Schema: s,
+	}
+}
+
+// snapshotRepositorySourceSchema is the settings of source only repository
+// The delegated repository is set with one typed block
+func snapshotRepositorySourceSchema() *schema.Resource {
+	delegateTypes := make([]string, 0, len(snapshotRepositorySourceDelegateTypes))
+	for _, delegateType := range snapshotRepositorySourceDelegateTypes {
+		delegateTypes = append(delegateTypes, fmt.Sprintf("source.0.%s", delegateType))
+	}
+
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"fs": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: delegateTypes,
+				Elem:         snapshotRepositoryFsSchema(),
+			},
+			"s3": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: delegateTypes,
+				Elem:         snapshotRepositoryS3Schema(),
+			},
+			"gcs": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: delegateTypes,
+				Elem:         snapshotRepositoryGcsSchema(),
+			},
+			"azure": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: delegateTypes,
+				Elem:         snapshotRepositoryAzureSchema(),
+			},
+		},
+	}
+}
+
+// snapshotRepositoryTypeSchema return the schema of typed block
+func snapshotRepositoryTypeSchema(repositoryType string) *schema.Resource {
+	switch repositoryType {
+	case "fs":
+		return snapshotRepositoryFsSchema()
+	case "url":
+		return snapshotRepositoryURLSchema()
+	case "s3":
+		return snapshotRepositoryS3Schema()
+	case "gcs":
+		return snapshotRepositoryGcsSchema()
+	case "azure":
+		return snapshotRepositoryAzureSchema()
+	case "source":
+		return snapshotRepositorySourceSchema()
+	default:
+		return nil
+	}
+}
+
+// snapshotRepositoryTypeSupported return true if the repository type can be managed with typed block
+func snapshotRepositoryTypeSupported(repositoryType string) bool {
+	for _, t := range snapshotRepositoryTypes {
+		if t == repositoryType {
+			return true
+		}
+	}
+	return false
+}
+
+// expandSnapshotRepository convert typed block to repository settings
+func expandSnapshotRepository(repositoryType string, raw map[string]interface{}) map[string]interface{} {
+	settings := map[string]interface{}{}
+
+	if repositoryType == "source" {
+		for _, delegateType := range snapshotRepositorySourceDelegateTypes {
+			if raws := raw[delegateType].([]interface{}); len(raws) > 0 && raws[0] != nil {
+				settings = expandSnapshotRepository(delegateType, raws[0].(map[string]interface{}))
+				settings["delegate_type"] = delegateType
+				break
+			}
+		}
+		return settings
+	}
+
+	for key, s := range snapshotRepositoryTypeSchema(repositoryType).Schema {
+		switch s.Type {
+		case schema.TypeBool:
+			settings[key] = raw[key].(bool)
+		case schema.TypeInt:
+			// When the setting has default value, zero can only come from configuration
+			if raw[key].(int) != 0 || s.Default != nil {
+				settings[key] = raw[key].(int)
+			}
+		case schema.TypeString:
+			if raw[key].(string) != "" {
+				settings[key] = raw[key].(string)
+			}
+		}
+	}
+
+	return settings
+}
+
+// flattenSnapshotRepository convert repository settings to typed block
+// Elasticsearch return all settings as string
+func flattenSnapshotRepository(repo *olivere.SnapshotRepositoryMetaData) ([]interface{}, error) {
+	repositoryType := repo.Type
+	settings := repo.Settings
+	if settings == nil {
+		settings = map[string]interface{}{}
+	}
+
+	if repositoryType == "source" {
+		delegateType := fmt.Sprintf("%v", settings["delegate_type"])
+		delegateSettings := make(map[string]interface{}, len(settings))
+		for key, value := range settings {
+			if key != "delegate_type" {
+				delegateSettings[key] = value
+			}
+		}
+		flattenDelegate, err := flattenSnapshotRepository(&olivere.SnapshotRepositoryMetaData{
+			Type:     delegateType,
+			Settings: delegateSettings,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{
+			map[string]interface{}{
+				delegateType: flattenDelegate,
+			},
+		}, nil
+	}
+
+	repositorySchema := snapshotRepositoryTypeSchema(repositoryType)
+	if repositorySchema == nil {
+		return nil, fmt.Errorf("Snapshot repository type %s is not supported", repositoryType)
+	}
+
+	tfMap := make(map[string]interface{})
+	for key, s := range repositorySchema.Schema {
+		value, ok := settings[key]
+		if !ok || value == nil {
+			if s.Default != nil {
+				tfMap[key] = s.Default
+			}
+			continue
+		}
+		valueStr := fmt.Sprintf("%v", value)
+
+		switch s.Type {
+		case schema.TypeBool:
+			b, err := strconv.ParseBool(valueStr)
+			if err != nil {
+				return nil, fmt.Errorf("Error when convert setting %s to bool: %w", key, err)
+			}
+			tfMap[key] = b
+		case schema.TypeInt:
+			i, err := strconv.Atoi(valueStr)
+			if err != nil {
+				return nil, fmt.Errorf("Error when convert setting %s to int: %w", key, err)
+			}
+			tfMap[key] = i
+		default:
+			tfMap[key] = valueStr
+		}
+	}
+
+	return []interface{}{tfMap}, nil
+}
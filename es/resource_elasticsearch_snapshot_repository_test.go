@@ -32,9 +32,28 @@ func TestAccElasticsearchSnapshotRepository(t *testing.T) {
 				),
 			},
 			{
-				ResourceName:      "elasticsearch_snapshot_repository.test",
-				ImportState:       true,
-				ImportStateVerify: true,
+				Config: testElasticsearchSnapshotRepositoryFs,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSnapshotRepositoryExists("elasticsearch_snapshot_repository.test"),
+					resource.TestCheckResourceAttr("elasticsearch_snapshot_repository.test", "type", "fs"),
+					resource.TestCheckResourceAttr("elasticsearch_snapshot_repository.test", "fs.0.compress", "false"),
+					resource.TestCheckResourceAttrSet("elasticsearch_snapshot_repository.test", "verified_nodes.0"),
+					resource.TestCheckResourceAttrSet("elasticsearch_snapshot_repository.test", "deleted_bytes"),
+				),
+			},
+			{
+				Config: testElasticsearchSnapshotRepositorySource,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchSnapshotRepositoryExists("elasticsearch_snapshot_repository.test"),
+					resource.TestCheckResourceAttr("elasticsearch_snapshot_repository.test", "type", "source"),
+					resource.TestCheckResourceAttr("elasticsearch_snapshot_repository.test", "source.0.fs.0.location", "/tmp"),
+				),
+			},
+			{
+				ResourceName:            "elasticsearch_snapshot_repository.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"verify", "cleanup_on_apply", "verified_nodes", "deleted_bytes", "deleted_blobs"},
 			},
 		},
 	})
@@ -111,3 +130,28 @@ resource "elasticsearch_snapshot_repository" "test" {
   }
 }
 `
+
+var testElasticsearchSnapshotRepositoryFs = `
+resource "elasticsearch_snapshot_repository" "test" {
+  name		= "terraform-test"
+  verify           = true
+  cleanup_on_apply = true
+  fs {
+	location                   = "/tmp"
+	compress                   = false
+	chunk_size                 = "1gb"
+	max_snapshot_bytes_per_sec = "20mb"
+  }
+}
+`
+
+var testElasticsearchSnapshotRepositorySource = `
+resource "elasticsearch_snapshot_repository" "test" {
+  name		= "terraform-test"
+  source {
+	fs {
+	  location = "/tmp"
+	}
+  }
+}
+`
@@ -7,12 +7,18 @@
 package es
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"time"
 
 	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	olivere "github.com/olivere/elastic/v7"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -25,7 +31,7 @@ func resourceElasticsearchWatcher() *schema.Resource {
 		Delete: resourceElasticsearchWatcherDelete,
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceElasticsearchWatcherImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -64,6 +70,34 @@ func resourceElasticsearchWatcher() *schema.Resource {
 				Optional:         true,
 				DiffSuppressFunc: suppressEquivalentJSON,
 			},
+			"active": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"state_active": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"last_checked": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"last_met_condition": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"execution_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"actions_ack_state": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 		},
 	}
 }
@@ -72,7 +106,7 @@ func resourceElasticsearchWatcher() *schema.Resource {
 func resourceElasticsearchWatcherCreate(d *schema.ResourceData, meta interface{}) (err error) {
 	name := d.Get("name").(string)
 
-	err = createWatcher(d, meta)
+	err = createWatcher(d, d.Get("active").(bool), meta)
 	if err != nil {
 		return err
 	}
@@ -90,18 +124,19 @@ func resourceElasticsearchWatcherRead(d *schema.ResourceData, meta interface{})
 
 	log.Debugf("Watcher id:  %s", id)
 
-	client := meta.(eshandler.ElasticsearchHandler)
-	watcher, err := client.WatchGet(id)
+	watcherResp, err := getWatcher(id, meta)
 	if err != nil {
 		return err
 	}
-	if watcher == nil {
+	if watcherResp == nil || watcherResp.Watch == nil {
 		fmt.Printf("[WARN] Watcher %s not found - removing from state", id)
 		log.Warnf("Watcher %s not found - removing from state", id)
 		d.SetId("")
 		return nil
 	}
 
+	watcher := watcherResp.Watch
+
 	if err = d.Set("name", id); err != nil {
 		return err
 	}
@@ -152,6 +187,11 @@ func resourceElasticsearchWatcherRead(d *schema.ResourceData, meta interface{})
 		}
 	}
 
+	// The active attribute is never read to not reactivate a watch that has been deactivated outside of Terraform
+	if err = setWatcherStatus(d, watcherResp.Status); err != nil {
+		return err
+	}
+
 	log.Infof("Read watcher %s successfully", id)
 
 	return nil
@@ -159,7 +199,17 @@ func resourceElasticsearchWatcherRead(d *schema.ResourceData, meta interface{})
 
 // resourceElasticsearchWatcherUpdate update existing watcher in Elasticsearch
 func resourceElasticsearchWatcherUpdate(d *schema.ResourceData, meta interface{}) (err error) {
-	err = createWatcher(d, meta)
+	// Keep the current state of the watch, except if active change
+	active := d.Get("state_active").(bool)
+	if d.HasChange("active") {
+		active = d.Get("active").(bool)
+	}
+
+	if d.HasChanges("trigger", "input", "condition", "actions", "metadata", "throttle_period") {
+		err = createWatcher(d, active, meta)
+	} else {
+		err = activateWatcher(d.Id(), active, meta)
+	}
 	if err != nil {
 		return err
 	}
@@ -169,6 +219,22 @@ func resourceElasticsearchWatcherUpdate(d *schema.ResourceData, meta interface{}
 	return resourceElasticsearchWatcherRead(d, meta)
 }
 
+// resourceElasticsearchWatcherImport import existing watch and its current active state
+func resourceElasticsearchWatcherImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := resourceElasticsearchWatcherRead(d, meta); err != nil {
+		return nil, err
+	}
+	if d.Id() == "" {
+		return nil, errors.New("Watcher not found")
+	}
+
+	if err := d.Set("active", d.Get("state_active").(bool)); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
 // resourceElasticsearchWatcherDelete delete existing watcher in Elasticsearch
 func resourceElasticsearchWatcherDelete(d *schema.ResourceData, meta interface{}) (err error) {
 
@@ -189,7 +255,8 @@ func resourceElasticsearchWatcherDelete(d *schema.ResourceData, meta interface{}
 }
 
 // createWatcher create or update watcher in Elasticsearch
-func createWatcher(d *schema.ResourceData, meta interface{}) (err error) {
+// The active state is always set, else Elasticsearch activate the watch
+func createWatcher(d *schema.ResourceData, active bool, meta interface{}) (err error) {
 	name := d.Get("name").(string)
 	triggerStr := d.Get("trigger").(string)
 	inputStr := d.Get("input").(string)
@@ -215,8 +282,6 @@ func createWatcher(d *schema.ResourceData, meta interface{}) (err error) {
 		return err
 	}
 
-	client := meta.(eshandler.ElasticsearchHandler)
-
 	data := &olivere.XPackWatch{
 		Trigger:        *trigger,
 		Input:          *input,
@@ -228,7 +293,135 @@ func createWatcher(d *schema.ResourceData, meta interface{}) (err error) {
 		data.Metadata = metadata.(map[string]interface{})
 	}
 
-	if err = client.WatchUpdate(name, data); err != nil {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Watcher.PutWatch(
+		name,
+		client.API.Watcher.PutWatch.WithBody(bytes.NewReader(b)),
+		client.API.Watcher.PutWatch.WithActive(active),
+		client.API.Watcher.PutWatch.WithContext(context.Background()),
+		client.API.Watcher.PutWatch.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when add watch %s: %s", name, res.String())
+	}
+
+	return nil
+}
+
+// getWatcher return the watch with its status or nil if not exist
+func getWatcher(name string, meta interface{}) (watcher *olivere.XPackWatcherGetWatchResponse, err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Watcher.GetWatch(
+		name,
+		client.API.Watcher.GetWatch.WithContext(context.Background()),
+		client.API.Watcher.GetWatch.WithPretty(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("Error when get watch %s: %s", name, res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	watcher = &olivere.XPackWatcherGetWatchResponse{}
+	if err = json.Unmarshal(b, watcher); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Get watch %s successfully:\n%s", name, string(b))
+
+	return watcher, nil
+}
+
+// activateWatcher activate or deactivate the watch
+func activateWatcher(name string, active bool, meta interface{}) (err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+
+	var res *esapi.Response
+	if active {
+		res, err = client.API.Watcher.ActivateWatch(
+			name,
+			client.API.Watcher.ActivateWatch.WithContext(context.Background()),
+			client.API.Watcher.ActivateWatch.WithPretty(),
+		)
+	} else {
+		res, err = client.API.Watcher.DeactivateWatch(
+			name,
+			client.API.Watcher.DeactivateWatch.WithContext(context.Background()),
+			client.API.Watcher.DeactivateWatch.WithPretty(),
+		)
+	}
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when set active to %t on watch %s: %s", active, name, res.String())
+	}
+
+	log.Infof("Set active to %t on watch %s successfully", active, name)
+
+	return nil
+}
+
+// setWatcherStatus set the computed attributes from the watch status
+func setWatcherStatus(d *schema.ResourceData, status *olivere.XPackWatchStatus) (err error) {
+	var (
+		active           bool
+		lastChecked      string
+		lastMetCondition string
+		executionState   string
+	)
+	actionsAckState := map[string]interface{}{}
+
+	if status != nil {
+		if status.State != nil {
+			active = status.State.Active
+		}
+		if status.LastChecked != nil {
+			lastChecked = status.LastChecked.Format(time.RFC3339)
+		}
+		if status.LastMetCondition != nil {
+			lastMetCondition = status.LastMetCondition.Format(time.RFC3339)
+		}
+		executionState = status.ExecutionState
+		for name, action := range status.Actions {
+			if action != nil && action.AckStatus != nil {
+				actionsAckState[name] = action.AckStatus.State
+			}
+		}
+	}
+
+	if err = d.Set("state_active", active); err != nil {
+		return err
+	}
+	if err = d.Set("last_checked", lastChecked); err != nil {
+		return err
+	}
+	if err = d.Set("last_met_condition", lastMetCondition); err != nil {
+		return err
+	}
+	if err = d.Set("execution_state", executionState); err != nil {
+		return err
+	}
+	if err = d.Set("actions_ack_state", actionsAckState); err != nil {
 		return err
 	}
 
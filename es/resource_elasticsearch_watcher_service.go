@@ -0,0 +1,177 @@
+// Manage the watcher service state in elasticsearch
+// API documentation: https://www.elastic.co/guide/en/elasticsearch/reference/current/watcher-api-start.html
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/watcher-api-stats.html
+// Supported version:
+//  - v7
+//  - v8
+
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// WatcherStatsResponse is the watcher stats API response
+type WatcherStatsResponse struct {
+	ManuallyStopped bool `json:"manually_stopped"`
+	Stats           []struct {
+		NodeId       string `json:"node_id"`
+		WatcherState string `json:"watcher_state"`
+	} `json:"stats"`
+}
+
+// resourceElasticsearchWatcherService handle the watcher start / stop API call
+func resourceElasticsearchWatcherService() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceElasticsearchWatcherServiceCreate,
+		Read:   resourceElasticsearchWatcherServiceRead,
+		Update: resourceElasticsearchWatcherServiceUpdate,
+		Delete: resourceElasticsearchWatcherServiceDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"watcher_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"manually_stopped": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"nodes_state": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// resourceElasticsearchWatcherServiceCreate start or stop the watcher service
+func resourceElasticsearchWatcherServiceCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	if err = setWatcherServiceState(d.Get("enabled").(bool), meta); err != nil {
+		return err
+	}
+	d.SetId("watcher")
+
+	return resourceElasticsearchWatcherServiceRead(d, meta)
+}
+
+// resourceElasticsearchWatcherServiceUpdate start or stop the watcher service
+func resourceElasticsearchWatcherServiceUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	if err = setWatcherServiceState(d.Get("enabled").(bool), meta); err != nil {
+		return err
+	}
+
+	return resourceElasticsearchWatcherServiceRead(d, meta)
+}
+
+// resourceElasticsearchWatcherServiceRead read the watcher state from stats
+// The watcher state is the state of all nodes when they are the same, else the first state that is not started
+func resourceElasticsearchWatcherServiceRead(d *schema.ResourceData, meta interface{}) (err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Watcher.Stats(
+		client.API.Watcher.Stats.WithContext(context.Background()),
+		client.API.Watcher.Stats.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when get watcher stats: %s", res.String())
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	stats := &WatcherStatsResponse{}
+	if err = json.Unmarshal(b, stats); err != nil {
+		return err
+	}
+
+	watcherState := ""
+	nodesState := make(map[string]interface{}, len(stats.Stats))
+	for _, node := range stats.Stats {
+		nodesState[node.NodeId] = node.WatcherState
+		if watcherState == "" || (watcherState == "started" && node.WatcherState != "started") {
+			watcherState = node.WatcherState
+		}
+	}
+
+	log.Debugf("Watcher state: %s", watcherState)
+
+	if err = d.Set("watcher_state", watcherState); err != nil {
+		return err
+	}
+	if err = d.Set("manually_stopped", stats.ManuallyStopped); err != nil {
+		return err
+	}
+	if err = d.Set("nodes_state", nodesState); err != nil {
+		return err
+	}
+	// starting is considered as enabled, the service will be started once the watches are loaded
+	if err = d.Set("enabled", watcherState == "started" || watcherState == "starting"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resourceElasticsearchWatcherServiceDelete start the watcher service, it's the default state
+func resourceElasticsearchWatcherServiceDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	if err = setWatcherServiceState(true, meta); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// setWatcherServiceState start or stop the watcher service
+func setWatcherServiceState(enabled bool, meta interface{}) (err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+
+	var res *esapi.Response
+	if enabled {
+		res, err = client.API.Watcher.Start(
+			client.API.Watcher.Start.WithContext(context.Background()),
+			client.API.Watcher.Start.WithPretty(),
+		)
+	} else {
+		res, err = client.API.Watcher.Stop(
+			client.API.Watcher.Stop.WithContext(context.Background()),
+			client.API.Watcher.Stop.WithPretty(),
+		)
+	}
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when set watcher enabled to %t: %s", enabled, res.String())
+	}
+
+	log.Infof("Set watcher enabled to %t successfully", enabled)
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package es
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccElasticsearchWatcherService(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testElasticsearchWatcherService,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchWatcherServiceExists("elasticsearch_watcher_service.test"),
+					resource.TestCheckResourceAttr("elasticsearch_watcher_service.test", "enabled", "false"),
+					resource.TestCheckResourceAttr("elasticsearch_watcher_service.test", "manually_stopped", "true"),
+				),
+			},
+			{
+				Config: testElasticsearchWatcherServiceUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchWatcherServiceExists("elasticsearch_watcher_service.test"),
+					resource.TestCheckResourceAttr("elasticsearch_watcher_service.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("elasticsearch_watcher_service.test", "manually_stopped", "false"),
+				),
+			},
+			{
+				ResourceName:            "elasticsearch_watcher_service.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"watcher_state", "nodes_state"},
+			},
+		},
+	})
+}
+
+func testCheckElasticsearchWatcherServiceExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No watcher service ID is set")
+		}
+		if rs.Primary.Attributes["watcher_state"] == "" {
+			return fmt.Errorf("No watcher state is set")
+		}
+
+		return nil
+	}
+}
+
+var testElasticsearchWatcherService = `
+resource "elasticsearch_watcher_service" "test" {
+  enabled = false
+}
+`
+
+var testElasticsearchWatcherServiceUpdate = `
+resource "elasticsearch_watcher_service" "test" {
+  enabled = true
+}
+`
@@ -23,12 +23,29 @@ func TestAccElasticsearchWatcher(t *testing.T) {
 				Config: testElasticsearchWatcher,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckElasticsearchWatcherExists("elasticsearch_watcher.test"),
+					resource.TestCheckResourceAttr("elasticsearch_watcher.test", "state_active", "true"),
+				),
+			},
+			{
+				// A watch deactivated outside of Terraform must stay inactive
+				PreConfig: func() {
+					if err := activateWatcher("terraform-test", false, testAccProvider.Meta()); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testElasticsearchWatcher,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckElasticsearchWatcherExists("elasticsearch_watcher.test"),
+					resource.TestCheckResourceAttr("elasticsearch_watcher.test", "active", "true"),
+					resource.TestCheckResourceAttr("elasticsearch_watcher.test", "state_active", "false"),
 				),
 			},
 			{
 				Config: testElasticsearchWatcherUpdate,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckElasticsearchWatcherExists("elasticsearch_watcher.test"),
+					resource.TestCheckResourceAttr("elasticsearch_watcher.test", "active", "false"),
+					resource.TestCheckResourceAttr("elasticsearch_watcher.test", "state_active", "false"),
 				),
 			},
 			{
@@ -154,6 +171,7 @@ EOF
 var testElasticsearchWatcherUpdate = `
 resource "elasticsearch_watcher" "test" {
   name		= "terraform-test"
+  active	= false
   trigger	= <<EOF
 {
 	"schedule" : { "cron" : "1 0/1 * * * ?" }
@@ -0,0 +1,81 @@
+package es
+
+import (
+	"context"
+
+	eshandler "github.com/disaster37/es-handler/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// securityCacheFields is the list of fields that only change the cache behavior, not the security object
+var securityCacheFields = []string{"clear_cache_on_change", "clear_cache_realms"}
+
+// clearSecurityCacheOnChange clear the role cache and the realm cache when clear_cache_on_change is enabled
+// It permit to apply the security changes immediately for users on realms with cache like LDAP or PKI
+func clearSecurityCacheOnChange(d *schema.ResourceData, roles []string, usernames []string, meta interface{}) (err error) {
+	if !d.Get("clear_cache_on_change").(bool) {
+		return nil
+	}
+
+	if len(roles) > 0 {
+		if err = clearSecurityRoleCache(roles, meta); err != nil {
+			return err
+		}
+	}
+
+	realms := convertArrayInterfaceToArrayString(d.Get("clear_cache_realms").(*schema.Set).List())
+	if len(realms) == 0 {
+		realms = []string{"*"}
+	}
+
+	return clearSecurityRealmCache(realms, usernames, meta)
+}
+
+// clearSecurityRoleCache evict the roles from the native role cache
+func clearSecurityRoleCache(roles []string, meta interface{}) (err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	res, err := client.API.Security.ClearCachedRoles(
+		roles,
+		client.API.Security.ClearCachedRoles.WithContext(context.Background()),
+		client.API.Security.ClearCachedRoles.WithPretty(),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when clear cache of roles %v: %s", roles, res.String())
+	}
+
+	log.Debugf("Clear cache of roles %v successfully", roles)
+
+	return nil
+}
+
+// clearSecurityRealmCache evict the users from the realm cache
+// All users are evicted if usernames is empty
+func clearSecurityRealmCache(realms []string, usernames []string, meta interface{}) (err error) {
+	client := meta.(eshandler.ElasticsearchHandler).Client()
+	opts := []func(*esapi.SecurityClearCachedRealmsRequest){
+		client.API.Security.ClearCachedRealms.WithContext(context.Background()),
+		client.API.Security.ClearCachedRealms.WithPretty(),
+	}
+	if len(usernames) > 0 {
+		opts = append(opts, client.API.Security.ClearCachedRealms.WithUsernames(usernames...))
+	}
+	res, err := client.API.Security.ClearCachedRealms(realms, opts...)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return errors.Errorf("Error when clear cache of realms %v: %s", realms, res.String())
+	}
+
+	log.Debugf("Clear cache of realms %v successfully", realms)
+
+	return nil
+}
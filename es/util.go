@@ -3,6 +3,10 @@ package es
 import (
 	"encoding/json"
 	"reflect"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
 )
 
 // optionalInterfaceJSON permit to convert string as json object
@@ -55,3 +59,98 @@ func convertInterfaceToJsonString(object interface{}) (string, error) {
 
 	return string(b), nil
 }
+
+// getString return the string value of the key or empty string if not set
+func getString(raw map[string]interface{}, key string) string {
+	if v, ok := raw[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// getInt return the int value of the key or 0 if not set
+func getInt(raw map[string]interface{}, key string) int {
+	if v, ok := raw[key].(int); ok {
+		return v
+	}
+	return 0
+}
+
+// getBool return the bool value of the key or the default value if not set
+func getBool(raw map[string]interface{}, key string, defaultValue bool) bool {
+	if v, ok := raw[key].(bool); ok {
+		return v
+	}
+	return defaultValue
+}
+
+// getMapString return the map of string of the key or nil if not set
+func getMapString(raw map[string]interface{}, key string) map[string]string {
+	v, ok := raw[key].(map[string]interface{})
+	if !ok || len(v) == 0 {
+		return nil
+	}
+
+	data := make(map[string]string, len(v))
+	for k, value := range v {
+		data[k] = value.(string)
+	}
+	return data
+}
+
+// getClosestString return the candidate with the smallest edit distance from the value
+// It return empty string if no candidate is close enough
+func getClosestString(value string, candidates []string) string {
+	closest := ""
+	maxDistance := len(value)/3 + 1
+	for _, candidate := range candidates {
+		if distance := levenshteinDistance(value, candidate); distance <= maxDistance {
+			closest = candidate
+			maxDistance = distance - 1
+		}
+	}
+
+	return closest
+}
+
+// levenshteinDistance return the number of edits to transform a to b
+func levenshteinDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+// getWriteOnlyString return the value of write only attribute from configuration
+// The write only attributes are never on state, so d.Get always return empty string
+func getWriteOnlyString(d *schema.ResourceData, key string) (string, error) {
+	value, diags := d.GetRawConfigAt(cty.GetAttrPath(key))
+	if diags.HasError() {
+		return "", errors.Errorf("Error when read %s from configuration", key)
+	}
+	if value.IsNull() || !value.IsKnown() || !value.Type().Equals(cty.String) {
+		return "", nil
+	}
+
+	return value.AsString(), nil
+}
@@ -0,0 +1,383 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var byteSizeRegexp = regexp.MustCompile(`(?i)^(-1|0|\d+(\.\d+)?(b|kb|mb|gb|tb|pb))$`)
+var timeValueRegexp = regexp.MustCompile(`^(-1|0|\d+(d|h|m|s|ms|micros|nanos))$`)
+var timeValueUnits = map[string]time.Duration{
+	"d":      24 * time.Hour,
+	"h":      time.Hour,
+	"m":      time.Minute,
+	"s":      time.Second,
+	"ms":     time.Millisecond,
+	"micros": time.Microsecond,
+	"nanos":  time.Nanosecond,
+}
+
+// cronField describe one field of Elasticsearch cron expression
+type cronField struct {
+	name      string
+	min       int
+	max       int
+	names     []string
+	question  bool
+	lastDay   bool
+	weekDay   bool
+	nthOfWeek bool
+}
+
+var cronFields = []cronField{
+	{name: "seconds", min: 0, max: 59},
+	{name: "minutes", min: 0, max: 59},
+	{name: "hours", min: 0, max: 23},
+	{name: "day_of_month", min: 1, max: 31, question: true, lastDay: true, weekDay: true},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day_of_week", min: 1, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}, question: true, lastDay: true, nthOfWeek: true},
+	{name: "year", min: 1970, max: 2099},
+}
+
+// validateByteSize permit to check the value is an Elasticsearch byte size like 10mb
+func validateByteSize(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	if v != "" && !byteSizeRegexp.MatchString(v) {
+		errors = append(errors, fmt.Errorf("%s must be a byte size like 500mb or 1gb, got %s", k, v))
+	}
+
+	return warnings, errors
+}
+
+// validateTimeValue permit to check the value is an Elasticsearch time unit like 30d
+func validateTimeValue(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	if v != "" && !timeValueRegexp.MatchString(v) {
+		errors = append(errors, fmt.Errorf("%s must be a time value like 30d or 12h, got %s", k, v))
+	}
+
+	return warnings, errors
+}
+
+// parseTimeValue convert Elasticsearch time unit like 30d to duration
+func parseTimeValue(v string) (time.Duration, error) {
+	if v == "0" {
+		return 0, nil
+	}
+
+	matches := timeValueRegexp.FindStringSubmatch(v)
+	if matches == nil || matches[2] == "" {
+		return 0, fmt.Errorf("%s is not a time value like 30d or 12h", v)
+	}
+
+	i, err := strconv.ParseInt(strings.TrimSuffix(v, matches[2]), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(i) * timeValueUnits[matches[2]], nil
+}
+
+// validateCronExpression permit to check the value is an Elasticsearch cron expression
+// <seconds> <minutes> <hours> <day_of_month> <month> <day_of_week> [year]
+func validateCronExpression(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	parts := strings.Fields(v)
+	if len(parts) != 6 && len(parts) != 7 {
+		errors = append(errors, fmt.Errorf("%s must be a cron expression with 6 or 7 fields like `0 30 1 * * ?`, got %s", k, v))
+		return warnings, errors
+	}
+
+	for index, part := range parts {
+		if err := checkCronField(cronFields[index], strings.ToUpper(part)); err != nil {
+			errors = append(errors, fmt.Errorf("%s: %w", k, err))
+		}
+	}
+
+	if (parts[3] == "?") == (parts[5] == "?") {
+		errors = append(errors, fmt.Errorf("%s: you must specify `?` for either the day_of_month or day_of_week field, got %s", k, v))
+	}
+
+	return warnings, errors
+}
+
+// checkCronField check one field of cron expression
+func checkCronField(field cronField, value string) error {
+	if value == "?" {
+		if !field.question {
+			return fmt.Errorf("`?` is not allowed on %s field", field.name)
+		}
+		return nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		if item == "" {
+			return fmt.Errorf("empty value on %s field", field.name)
+		}
+
+		switch {
+		case field.lastDay && (item == "L" || item == "LW"):
+			if item == "LW" && !field.weekDay {
+				return fmt.Errorf("`LW` is not allowed on %s field", field.name)
+			}
+			continue
+		case field.lastDay && field.weekDay && strings.HasPrefix(item, "L-"):
+			if _, err := parseCronValue(field, strings.TrimPrefix(item, "L-")); err != nil {
+				return err
+			}
+			continue
+		case field.weekDay && strings.HasSuffix(item, "W"):
+			if _, err := parseCronValue(field, strings.TrimSuffix(item, "W")); err != nil {
+				return err
+			}
+			continue
+		case field.lastDay && field.nthOfWeek && strings.HasSuffix(item, "L"):
+			if _, err := parseCronValue(field, strings.TrimSuffix(item, "L")); err != nil {
+				return err
+			}
+			continue
+		case field.nthOfWeek && strings.Contains(item, "#"):
+			values := strings.SplitN(item, "#", 2)
+			if _, err := parseCronValue(field, values[0]); err != nil {
+				return err
+			}
+			if nth, err := strconv.Atoi(values[1]); err != nil || nth < 1 || nth > 5 {
+				return fmt.Errorf("invalid nth day %s on %s field, it must be between 1 and 5", values[1], field.name)
+			}
+			continue
+		}
+
+		base := item
+		if strings.Contains(item, "/") {
+			values := strings.SplitN(item, "/", 2)
+			base = values[0]
+			if step, err := strconv.Atoi(values[1]); err != nil || step < 1 {
+				return fmt.Errorf("invalid increment %s on %s field", values[1], field.name)
+			}
+		}
+
+		if base == "*" {
+			continue
+		}
+
+		bounds := strings.SplitN(base, "-", 2)
+		start, err := parseCronValue(field, bounds[0])
+		if err != nil {
+			return err
+		}
+		if len(bounds) == 2 {
+			end, err := parseCronValue(field, bounds[1])
+			if err != nil {
+				return err
+			}
+			if end < start && field.names == nil {
+				return fmt.Errorf("invalid range %s on %s field", base, field.name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseCronValue convert the number or the name to value and check it's in the allowed range
+func parseCronValue(field cronField, value string) (int, error) {
+	for index, name := range field.names {
+		if value == name {
+			return index + field.min, nil
+		}
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %s on %s field", value, field.name)
+	}
+	if i < field.min || i > field.max {
+		return 0, fmt.Errorf("value %d on %s field must be between %d and %d", i, field.name, field.min, field.max)
+	}
+
+	return i, nil
+}
+
+// validateRoleQuery permit to check the role query is a JSON object
+// When it is a template query, the mustache tags must be well formed
+func validateRoleQuery(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+	if v == "" {
+		return warnings, errors
+	}
+
+	query := map[string]any{}
+	if err := json.Unmarshal([]byte(v), &query); err != nil {
+		errors = append(errors, fmt.Errorf("%s must be a JSON object: %s", k, err.Error()))
+		return warnings, errors
+	}
+
+	// Only template query is rendered, so the mustache tags are checked only on it
+	_, isTemplate := query["template"]
+	for _, text := range getJSONStrings(query) {
+		if !strings.Contains(text, "{{") {
+			continue
+		}
+		if !isTemplate {
+			warnings = append(warnings, fmt.Sprintf("%s contains {{ but it's not a template query, so it's not rendered as mustache template. Wrap it like {\"template\": {\"source\": ...}} if you use mustache tags", k))
+			break
+		}
+		if err := checkMustacheTemplate(text); err != nil {
+			errors = append(errors, fmt.Errorf("%s has invalid mustache template %s: %s", k, text, err.Error()))
+		}
+	}
+
+	return warnings, errors
+}
+
+// validateMustacheTemplate permit to check the value is a well formed mustache template
+func validateMustacheTemplate(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	if err := checkMustacheTemplate(v); err != nil {
+		errors = append(errors, fmt.Errorf("%s has invalid mustache template %s: %s", k, v, err.Error()))
+	}
+
+	return warnings, errors
+}
+
+// validateRoleMappingRules permit to check the role mapping rules is a JSON object with well formed rule DSL
+func validateRoleMappingRules(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+	if v == "" {
+		return warnings, errors
+	}
+
+	rule := map[string]any{}
+	if err := json.Unmarshal([]byte(v), &rule); err != nil {
+		errors = append(errors, fmt.Errorf("%s must be a JSON object: %s", k, err.Error()))
+		return warnings, errors
+	}
+	if err := checkRoleMappingRule(rule); err != nil {
+		errors = append(errors, fmt.Errorf("%s is invalid: %s", k, err.Error()))
+	}
+
+	return warnings, errors
+}
+
+// validateRoleMappingRuleField permit to check the value is a user field of role mapping rule
+func validateRoleMappingRuleField(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+
+	if err := checkRoleMappingRuleField(v); err != nil {
+		errors = append(errors, fmt.Errorf("%s is invalid: %s", k, err.Error()))
+	}
+
+	return warnings, errors
+}
+
+// getJSONStrings return all string values of decoded JSON
+func getJSONStrings(raw any) (texts []string) {
+	switch value := raw.(type) {
+	case string:
+		texts = append(texts, value)
+	case map[string]any:
+		for _, item := range value {
+			texts = append(texts, getJSONStrings(item)...)
+		}
+	case []any:
+		for _, item := range value {
+			texts = append(texts, getJSONStrings(item)...)
+		}
+	}
+
+	return texts
+}
+
+// checkMustacheTemplate check the mustache tags are closed and the sections are well nested
+func checkMustacheTemplate(text string) error {
+	sections := make([]string, 0)
+	for {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			break
+		}
+		text = text[start+2:]
+
+		closeTag := "}}"
+		if strings.HasPrefix(text, "{") {
+			closeTag = "}}}"
+		}
+		end := strings.Index(text, closeTag)
+		if end < 0 {
+			return fmt.Errorf("the tag {{%s is not closed", text)
+		}
+		tag := strings.TrimSpace(strings.TrimPrefix(text[:end], "{"))
+		text = text[end+len(closeTag):]
+
+		if tag == "" {
+			return fmt.Errorf("empty tag")
+		}
+		switch tag[0] {
+		case '!':
+			continue
+		case '=':
+			// Custom delimiters are not checked
+			return nil
+		case '#', '^':
+			sections = append(sections, strings.TrimSpace(tag[1:]))
+			continue
+		case '/':
+			name := strings.TrimSpace(tag[1:])
+			if len(sections) == 0 {
+				return fmt.Errorf("the section %s is closed but never opened", name)
+			}
+			if sections[len(sections)-1] != name {
+				return fmt.Errorf("the section %s is closed but the section %s is not", name, sections[len(sections)-1])
+			}
+			sections = sections[:len(sections)-1]
+			continue
+		case '&', '>':
+			tag = strings.TrimSpace(tag[1:])
+		}
+		if tag == "" {
+			return fmt.Errorf("empty tag")
+		}
+	}
+
+	if len(sections) > 0 {
+		return fmt.Errorf("the section %s is not closed", sections[len(sections)-1])
+	}
+
+	return nil
+}